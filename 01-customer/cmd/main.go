@@ -1,21 +1,101 @@
 package main
 
 import (
-	"github.com/adilsonmenechini/golabbank/config"
-	"github.com/adilsonmenechini/golabbank/internal/customer/repositories"
-	"github.com/adilsonmenechini/golabbank/internal/customer/usecases"
-	"github.com/adilsonmenechini/golabbank/internal/delivery/handler"
-	"github.com/adilsonmenechini/golabbank/internal/delivery/router"
-	"github.com/adilsonmenechini/golabbank/pkg/database"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/config"
+	"github.com/adilsonmenechini/golabbank/customer/internal/customer/repositories"
+	"github.com/adilsonmenechini/golabbank/customer/internal/customer/usecases"
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/handler"
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/router"
+	tokenrepo "github.com/adilsonmenechini/golabbank/customer/internal/token/repositories"
+	tokens "github.com/adilsonmenechini/golabbank/customer/internal/token/usecases"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/database"
+	"github.com/adilsonmenechini/golabbank/platform/loginguard"
+	"github.com/adilsonmenechini/golabbank/platform/metrics"
+	"github.com/adilsonmenechini/golabbank/platform/notify"
+	"github.com/adilsonmenechini/golabbank/platform/password"
+	"github.com/adilsonmenechini/golabbank/platform/server"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
 )
 
 func main() {
 
 	config.ParseEnvVariables()
-	dbcon := database.ConnectPSQL()
-	repo := repositories.NewCustomerRepository(dbcon)
-	usc := usecases.NewCustomerUseCase(repo)
-	hdl := handler.NewCustomerHandler(usc)
-	router.NewCustomerRouter(hdl).Router()
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+
+}
+
+// run serves until SIGINT or SIGTERM, then drains requests, stops the
+// background sweeps and closes the database and the span exporter.
+func run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
+	shutdown, err := tracing.Setup(ctx, "customer")
+	if err != nil {
+		return err
+	}
+	dbcon, err := database.ConnectPSQL(ctx)
+	if err != nil {
+		shutdown(ctx)
+		return err
+	}
+	metrics.RegisterDB(dbcon, os.Getenv("DB_NAME"))
+
+	h, err := newHandler(ctx, dbcon)
+	if err != nil {
+		dbcon.Close()
+		shutdown(ctx)
+		return err
+	}
+
+	srv := server.New(server.ConfigFromEnv(), h)
+	srv.OnShutdown(shutdown)
+	srv.OnShutdown(func(context.Context) error {
+		cancel()
+		return dbcon.Close()
+	})
+	return srv.Run(ctx)
+}
+
+// newHandler wires the customer service on top of dbcon.
+func newHandler(ctx context.Context, dbcon *sql.DB) (http.Handler, error) {
+	repo := repositories.NewCustomerRepository(dbcon)
+	tokenUC := tokens.NewTokenUseCase(tokenrepo.NewTokenRepository(dbcon), repo, config.GetDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour))
+	policy, err := password.PolicyFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("loading password policy: %w", err)
+	}
+	hasher, err := password.HasherFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("loading password hasher: %w", err)
+	}
+	auditor := audit.NewLogAuditor()
+	guard, err := loginguard.FromEnv(dbcon, auditor)
+	if err != nil {
+		return nil, fmt.Errorf("loading login guard: %w", err)
+	}
+	go guard.Sweep(ctx, time.Hour)
+	notifier, err := notify.FromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("loading notifier: %w", err)
+	}
+	reset := usecases.PasswordResetConfig{
+		TTL: config.GetDuration("PASSWORD_RESET_TTL", 30*time.Minute),
+		URL: os.Getenv("PASSWORD_RESET_URL"),
+	}
+	usc := usecases.NewCustomerUseCase(repo, repositories.NewPasswordResetRepository(dbcon), tokenUC, policy, hasher, guard, notifier, auditor, reset)
+	hdl := handler.NewCustomerHandler(usc, tokenUC)
+	dir := handler.NewDirectoryHandler(usc, tokenUC)
+	return router.NewCustomerRouter(hdl, dir, tokenUC).Router(ctx)
 }
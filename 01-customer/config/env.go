@@ -1,30 +1,22 @@
 package config
 
 import (
-	"log"
 	"path/filepath"
 	"runtime"
+	"time"
 
-	"github.com/joho/godotenv"
+	platformconfig "github.com/adilsonmenechini/golabbank/platform/config"
 )
 
-type EnvConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBname   string
-	Token    string
-}
-
 func ParseEnvVariables() {
 	_, b, _, _ := runtime.Caller(0)
 	basepath := filepath.Dir(b)
 
-	err := godotenv.Load(basepath + "/../deploy/.env")
-
-	if err != nil {
-		log.Fatalf("Error loading .env files")
-	}
+	platformconfig.Load(basepath + "/../deploy/.env")
+}
 
+// GetDuration reads a duration such as "24h" from the environment, falling
+// back to def when the variable is unset or malformed.
+func GetDuration(key string, def time.Duration) time.Duration {
+	return platformconfig.GetDuration(key, def)
 }
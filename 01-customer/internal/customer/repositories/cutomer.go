@@ -4,8 +4,9 @@ import (
 	"context"
 	"database/sql"
 
-	"github.com/adilsonmenechini/golabbank/internal/domain"
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/identity"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
 )
 
 type (
@@ -15,6 +16,8 @@ type (
 		GetEmailCustomer(ctx context.Context, email string) (domain.Customer, error)
 		GetIDCustomer(ctx context.Context, id string) (domain.Customer, error)
 		UpdatePasswordCustomer(ctx context.Context, customer domain.Customer) error
+		UpdateRoleCustomer(ctx context.Context, id string, role identity.Role) error
+		ListCustomers(ctx context.Context, limit, offset int) ([]domain.Customer, error)
 	}
 
 	customerRepository struct {
@@ -31,11 +34,13 @@ func NewCustomerRepository(DB *sql.DB) CustomerRepository {
 }
 
 const (
-	createCustomer         = `INSERT INTO customers (id, name, email, password, created_at) VALUES ( $1, $2, $3, $4, $5)`
+	createCustomer         = `INSERT INTO customers (id, name, email, password, role, created_at) VALUES ( $1, $2, $3, $4, $5, $6)`
 	deleteCustomer         = `DELETE FROM customers WHERE id = $1`
-	getEmailCustomer       = `SELECT id, name, email, password, created_at FROM customers WHERE email = $1 LIMIT 1`
+	getEmailCustomer       = `SELECT id, name, email, password, role, created_at FROM customers WHERE email = $1 LIMIT 1`
 	updatePasswordCustomer = `UPDATE customers set password = $2 WHERE email = $1`
-	getIDCustomer          = `SELECT id, name, email, password, created_at FROM customers WHERE id = $1 LIMIT 1`
+	updateRoleCustomer     = `UPDATE customers set role = $2 WHERE id = $1`
+	getIDCustomer          = `SELECT id, name, email, password, role, created_at FROM customers WHERE id = $1 LIMIT 1`
+	listCustomers          = `SELECT id, name, email, password, role, created_at FROM customers ORDER BY created_at, id LIMIT $1 OFFSET $2`
 )
 
 func (ra *customerRepository) CreateCustomer(ctx context.Context, customer domain.Customer) error {
@@ -48,6 +53,7 @@ func (ra *customerRepository) CreateCustomer(ctx context.Context, customer domai
 		custNew.Name,
 		custNew.Email,
 		custNew.Password,
+		custNew.Role,
 		custNew.CreatedAt,
 	)
 	if err != nil {
@@ -80,6 +86,7 @@ func (ra *customerRepository) GetEmailCustomer(ctx context.Context, email string
 		&i.Name,
 		&i.Email,
 		&i.Password,
+		&i.Role,
 		&i.CreatedAt,
 	)
 	if err != nil {
@@ -97,6 +104,7 @@ func (ra *customerRepository) GetIDCustomer(ctx context.Context, id string) (dom
 		&i.Name,
 		&i.Email,
 		&i.Password,
+		&i.Role,
 		&i.CreatedAt,
 	)
 	if err != nil {
@@ -105,3 +113,41 @@ func (ra *customerRepository) GetIDCustomer(ctx context.Context, id string) (dom
 
 	return i, err
 }
+
+// UpdateRoleCustomer implements CustomerRepository.
+func (ra *customerRepository) UpdateRoleCustomer(ctx context.Context, id string, role identity.Role) error {
+	res, err := ra.db.ExecContext(ctx, updateRoleCustomer, id, role)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrCustomerNotFound
+	}
+	return err
+}
+
+// ListCustomers implements CustomerRepository. Customers come oldest first.
+func (ra *customerRepository) ListCustomers(ctx context.Context, limit, offset int) ([]domain.Customer, error) {
+	rows, err := ra.db.QueryContext(ctx, listCustomers, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []domain.Customer
+	for rows.Next() {
+		var i domain.Customer
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Password,
+			&i.Role,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, i)
+	}
+	return list, rows.Err()
+}
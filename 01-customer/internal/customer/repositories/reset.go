@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type (
+	PasswordResetRepository interface {
+		CreateReset(ctx context.Context, r *domain.PasswordReset) error
+		GetReset(ctx context.Context, tokenHash string) (*domain.PasswordReset, error)
+		RedeemReset(ctx context.Context, tokenHash, passwordHash string) (string, error)
+	}
+
+	passwordResetRepository struct {
+		logger *utils.Logger
+		db     *sql.DB
+	}
+)
+
+func NewPasswordResetRepository(DB *sql.DB) PasswordResetRepository {
+	return &passwordResetRepository{
+		logger: utils.NewLogger("passwordResetRepository"),
+		db:     DB,
+	}
+}
+
+// CreateReset implements PasswordResetRepository. Only the newest reset of a
+// customer is valid: earlier and expired ones are deleted with it.
+func (rr *passwordResetRepository) CreateReset(ctx context.Context, r *domain.PasswordReset) error {
+	tx, err := rr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, deleteCustomerResets, r.CustomerID, r.CreatedAt); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, createReset, r.ID, r.CustomerID, r.TokenHash, r.ExpiresAt, r.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetReset implements PasswordResetRepository. Unknown, used and expired
+// tokens all return ErrResetTokenInvalid.
+func (rr *passwordResetRepository) GetReset(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	var r domain.PasswordReset
+	err := rr.db.QueryRowContext(ctx, getReset, tokenHash).Scan(
+		&r.ID,
+		&r.CustomerID,
+		&r.TokenHash,
+		&r.ExpiresAt,
+		&r.CreatedAt,
+		&r.UsedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrResetTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !r.Usable(time.Now().UTC()) {
+		return nil, domain.ErrResetTokenInvalid
+	}
+	return &r, nil
+}
+
+// RedeemReset implements PasswordResetRepository. The reset is marked used
+// and the customer's password replaced in one transaction, so a token can
+// only ever set one password. It returns the customer's id.
+func (rr *passwordResetRepository) RedeemReset(ctx context.Context, tokenHash, passwordHash string) (string, error) {
+	tx, err := rr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var customerID string
+	err = tx.QueryRowContext(ctx, redeemReset, tokenHash, time.Now().UTC()).Scan(&customerID)
+	if err == sql.ErrNoRows {
+		return "", domain.ErrResetTokenInvalid
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := tx.ExecContext(ctx, updatePasswordByID, customerID, passwordHash); err != nil {
+		return "", err
+	}
+	return customerID, tx.Commit()
+}
+
+const (
+	createReset          = `INSERT INTO password_resets (id, customer_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)`
+	deleteCustomerResets = `DELETE FROM password_resets WHERE customer_id = $1 OR expires_at < $2`
+	getReset             = `SELECT id, customer_id, token_hash, expires_at, created_at, used_at FROM password_resets WHERE token_hash = $1`
+	redeemReset          = `UPDATE password_resets SET used_at = $2 WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2 RETURNING customer_id`
+	updatePasswordByID   = `UPDATE customers SET password = $2 WHERE id = $1`
+)
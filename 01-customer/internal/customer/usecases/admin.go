@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/identity"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+const (
+	defaultCustomerPage = 50
+	maxCustomerPage     = 200
+)
+
+// ListCustomers implements CustomerUseCase.
+func (u *customerUseCase) ListCustomers(ctx context.Context, req presenter.ListCustomersRequest) ([]presenter.CustomerResponse, error) {
+	ctx, span := tracing.Start(ctx, "customerUseCase.ListCustomers")
+	defer span.End()
+	if req.Limit <= 0 {
+		req.Limit = defaultCustomerPage
+	}
+	if req.Limit > maxCustomerPage {
+		req.Limit = maxCustomerPage
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	list, err := u.repo.ListCustomers(ctx, req.Limit, req.Offset)
+	if err != nil {
+		u.logger.WithContext(ctx).Errorf("error listing customers: %v", err)
+		return nil, err
+	}
+
+	res := make([]presenter.CustomerResponse, 0, len(list))
+	for _, cr := range list {
+		res = append(res, presenter.NewCustomerResponse(cr))
+	}
+	return res, nil
+}
+
+// SetRole implements CustomerUseCase. The customer's tokens are revoked, so
+// the new role takes effect at their next sign-in rather than whenever their
+// current access token expires. Admins cannot change their own role, which
+// keeps the last admin from locking everyone out of the back office.
+func (u *customerUseCase) SetRole(ctx context.Context, req presenter.SetRoleRequest) (*presenter.CustomerResponse, error) {
+	ctx, span := tracing.Start(ctx, "customerUseCase.SetRole")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		u.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+	role, err := identity.ParseRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+	tk, ok := utils.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+	if tk.ID == req.CustomerID {
+		return nil, domain.ErrOwnRole
+	}
+
+	err = u.repo.UpdateRoleCustomer(ctx, req.CustomerID, role)
+	if err == nil {
+		err = u.tokens.RevokeCustomer(ctx, req.CustomerID)
+	}
+	e := audit.Event{
+		Action:     "customer.role",
+		CustomerID: req.CustomerID,
+		Operator:   tk.ID,
+		Role:       tk.Role,
+		Resource:   req.CustomerID,
+		Outcome:    audit.OutcomeAllowed,
+		Detail:     "role set to " + string(role),
+	}
+	if err != nil {
+		e.Outcome = audit.OutcomeFailed
+		e.Detail = err.Error()
+	}
+	u.audit.Record(ctx, e)
+	if err != nil {
+		u.logger.WithContext(ctx).Errorf("error setting customer role: %v", err)
+		return nil, err
+	}
+
+	cr, err := u.repo.GetIDCustomer(ctx, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	res := presenter.NewCustomerResponse(cr)
+	return &res, nil
+}
@@ -2,99 +2,206 @@ package usecases
 
 import (
 	"context"
-
-	"github.com/adilsonmenechini/golabbank/internal/customer/repositories"
-	"github.com/adilsonmenechini/golabbank/internal/delivery/presenter"
-	"github.com/adilsonmenechini/golabbank/internal/domain"
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/customer/repositories"
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	tokens "github.com/adilsonmenechini/golabbank/customer/internal/token/usecases"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/loginguard"
+	"github.com/adilsonmenechini/golabbank/platform/notify"
+	"github.com/adilsonmenechini/golabbank/platform/password"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
 )
 
 type (
 	Write interface {
 		Create(ctx context.Context, req presenter.SignupRequest) error
 		Delete(ctx context.Context, id string) error
-		UpdatePassword(ctx context.Context, req presenter.CustomerUpdate) error
 	}
 	Reader interface {
 		FindByEmail(ctx context.Context, email string) (domain.Customer, error)
 		FindByID(ctx context.Context, id string) (domain.Customer, error)
 	}
+	Authenticator interface {
+		Authenticate(ctx context.Context, req presenter.SigninRequest) (domain.Customer, error)
+	}
+	Admin interface {
+		ListCustomers(ctx context.Context, req presenter.ListCustomersRequest) ([]presenter.CustomerResponse, error)
+		SetRole(ctx context.Context, req presenter.SetRoleRequest) (*presenter.CustomerResponse, error)
+	}
+	Passwords interface {
+		ChangePassword(ctx context.Context, req presenter.ChangePasswordRequest) (*presenter.TokenResponse, error)
+		ForgotPassword(ctx context.Context, req presenter.ForgotPasswordRequest) error
+		ResetPassword(ctx context.Context, req presenter.ResetPasswordRequest) error
+	}
 
 	CustomerUseCase interface {
 		Write
 		Reader
+		Authenticator
+		Passwords
+		Admin
+	}
+
+	// PasswordResetConfig sets how long a reset token lives and the page
+	// customers open it on; the token is appended as ?token=.
+	PasswordResetConfig struct {
+		TTL time.Duration
+		URL string
 	}
 
 	customerUseCase struct {
-		logger *utils.Logger
-		repo   repositories.CustomerRepository
+		logger   *utils.Logger
+		repo     repositories.CustomerRepository
+		resets   repositories.PasswordResetRepository
+		tokens   tokens.TokenUseCase
+		policy   *password.Policy
+		hasher   *password.Hasher
+		guard    *loginguard.Guard
+		notifier notify.Notifier
+		audit    audit.Auditor
+		reset    PasswordResetConfig
+		// resetSlots holds one token per ForgotPassword still running.
+		resetSlots chan struct{}
 	}
 )
 
-func NewCustomerUseCase(repo repositories.CustomerRepository) CustomerUseCase {
+func NewCustomerUseCase(repo repositories.CustomerRepository, resets repositories.PasswordResetRepository, tokenUC tokens.TokenUseCase, policy *password.Policy, hasher *password.Hasher, guard *loginguard.Guard, notifier notify.Notifier, auditor audit.Auditor, reset PasswordResetConfig) CustomerUseCase {
 	return &customerUseCase{
-		logger: utils.NewLogger("usecase"),
-		repo:   repo,
+		logger:   utils.NewLogger("usecaseCustomer"),
+		repo:     repo,
+		resets:   resets,
+		tokens:   tokenUC,
+		policy:   policy,
+		hasher:   hasher,
+		guard:    guard,
+		notifier: notifier,
+		audit:    auditor,
+		reset:    reset,
+
+		resetSlots: make(chan struct{}, maxResetsInFlight),
 	}
 }
 
 func (u *customerUseCase) Create(ctx context.Context, req presenter.SignupRequest) error {
+	ctx, span := tracing.Start(ctx, "customerUseCase.Create")
+	defer span.End()
 
-	input := domain.Customer{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password,
+	err := utils.ValidateStruct(req)
+
+	if err != nil {
+		u.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
 	}
 
-	err := utils.ValidateStruct(req)
+	if err := u.policy.Check("password", req.Password, req.Email, req.Name); err != nil {
+		return err
+	}
 
+	hash, err := u.hasher.Hash(req.Password)
 	if err != nil {
-		u.logger.Errorf("error validating request: %v", err)
+		u.logger.WithContext(ctx).Errorf("error hashing password: %v", err)
 		return err
 	}
 
+	input := domain.Customer{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: hash,
+	}
+
 	err = u.repo.CreateCustomer(ctx, input)
 
 	if err != nil {
-		u.logger.Errorf("error creating customer: %v", err)
+		u.logger.WithContext(ctx).Errorf("error creating customer: %v", err)
 		return err
 	}
 	return nil
 }
 
 func (u *customerUseCase) Delete(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "customerUseCase.Delete")
+	defer span.End()
 	err := u.repo.DeleteCustomer(ctx, id)
 	if err != nil {
-		u.logger.Errorf("error deleting customer: %v", err)
+		u.logger.WithContext(ctx).Errorf("error deleting customer: %v", err)
 		return err
 	}
 	return nil
 }
 
-func (u *customerUseCase) UpdatePassword(ctx context.Context, req presenter.CustomerUpdate) error {
-	acc := domain.Customer{
-		Email:    req.Email,
-		Password: req.Password,
+func (u *customerUseCase) FindByEmail(ctx context.Context, email string) (domain.Customer, error) {
+	ctx, span := tracing.Start(ctx, "customerUseCase.FindByEmail")
+	defer span.End()
+	return u.repo.GetEmailCustomer(ctx, email)
+}
+
+func (u *customerUseCase) FindByID(ctx context.Context, id string) (domain.Customer, error) {
+	ctx, span := tracing.Start(ctx, "customerUseCase.FindByID")
+	defer span.End()
+	return u.repo.GetIDCustomer(ctx, id)
+}
+
+// Authenticate implements CustomerUseCase. Attempts are refused while the
+// login guard holds the email or client IP back, and every wrong password or
+// unknown email counts against both. A hash written with an older scheme or
+// older parameters is replaced while the plain password is at hand.
+func (u *customerUseCase) Authenticate(ctx context.Context, req presenter.SigninRequest) (domain.Customer, error) {
+	ctx, span := tracing.Start(ctx, "customerUseCase.Authenticate")
+	defer span.End()
+	if err := u.guard.Check(ctx, req.Email, req.ClientIP); err != nil {
+		return domain.Customer{}, err
 	}
 
-	err := utils.ValidateStruct(acc)
+	cr, err := u.repo.GetEmailCustomer(ctx, req.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Take as long as a wrong password would, so the response time does
+		// not reveal which emails are registered.
+		u.hasher.VerifyDecoy(req.Password)
+		return domain.Customer{}, u.failSignin(ctx, req)
+	}
 	if err != nil {
-		u.logger.Errorf("error validating request: %v", err)
-		return err
+		u.logger.WithContext(ctx).Errorf("error getting customer: %v", err)
+		return domain.Customer{}, err
 	}
 
-	err = u.repo.UpdatePasswordCustomer(ctx, acc)
+	ok, rehash, err := u.hasher.Verify(req.Password, cr.Password)
 	if err != nil {
-		u.logger.Errorf("error updating password: %v", err)
-		return err
+		u.logger.WithContext(ctx).Errorf("error verifying password: %v", err)
+		return domain.Customer{}, err
+	}
+	if !ok {
+		return domain.Customer{}, u.failSignin(ctx, req)
+	}
+	if err := u.guard.Succeed(ctx, req.Email, req.ClientIP); err != nil {
+		u.logger.WithContext(ctx).Errorf("error clearing failed sign-ins: %v", err)
 	}
-	return nil
-}
 
-func (u *customerUseCase) FindByEmail(ctx context.Context, email string) (domain.Customer, error) {
-	return u.repo.GetEmailCustomer(ctx, email)
+	if rehash {
+		hash, err := u.hasher.Hash(req.Password)
+		if err == nil {
+			err = u.repo.UpdatePasswordCustomer(ctx, domain.Customer{Email: cr.Email, Password: hash})
+		}
+		if err != nil {
+			// The sign-in itself succeeded; the rehash is retried next time.
+			u.logger.WithContext(ctx).Errorf("error rehashing password: %v", err)
+		} else {
+			cr.Password = hash
+		}
+	}
+	return cr, nil
 }
 
-func (u *customerUseCase) FindByID(ctx context.Context, id string) (domain.Customer, error) {
-	return u.repo.GetIDCustomer(ctx, id)
+// failSignin reports a failed sign-in to the login guard and returns the
+// error the caller sees, which is the same whatever went wrong.
+func (u *customerUseCase) failSignin(ctx context.Context, req presenter.SigninRequest) error {
+	if err := u.guard.Fail(ctx, req.Email, req.ClientIP); err != nil {
+		u.logger.WithContext(ctx).Errorf("error counting failed sign-in: %v", err)
+	}
+	return domain.ErrInvalidCredentials
 }
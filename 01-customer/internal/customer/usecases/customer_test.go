@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/platform/password"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestSigninUpgradesBcryptHashes signs in with a password stored by bcrypt:
+// the sign-in succeeds and the stored hash is replaced by an Argon2id one.
+func TestSigninUpgradesBcryptHashes(t *testing.T) {
+	f := newPasswordFixture(t, time.Hour)
+	legacy, err := password.Bcrypt{Cost: bcrypt.MinCost}.Hash(currentPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := f.customers.customers["alice"]
+	alice.Password = legacy
+	f.customers.customers["alice"] = alice
+
+	cr, err := f.uc.Authenticate(context.Background(), presenter.SigninRequest{Email: "alice@example.com", Password: currentPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := f.customers.password("alice")
+	if !strings.HasPrefix(stored, "$argon2id$") || cr.Password != stored {
+		t.Fatalf("stored hash = %q after sign-in, want it upgraded to argon2id", stored)
+	}
+	if !f.passwordIs(t, currentPassword) {
+		t.Fatal("the upgraded hash does not verify")
+	}
+}
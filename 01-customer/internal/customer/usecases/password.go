@@ -0,0 +1,207 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/notify"
+	"github.com/adilsonmenechini/golabbank/platform/password"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+const (
+	// resetSendTimeout bounds the background work of one ForgotPassword.
+	resetSendTimeout = 30 * time.Second
+	// maxResetsInFlight bounds how many of them run at once.
+	maxResetsInFlight = 64
+)
+
+// ChangePassword implements CustomerUseCase. The caller proves they know the
+// current password, under the same login guard limits as a sign-in so that a
+// stolen access token does not allow unlimited guesses; every session is
+// then revoked and a fresh token pair returned, so only the device that
+// changed the password stays signed in.
+func (u *customerUseCase) ChangePassword(ctx context.Context, req presenter.ChangePasswordRequest) (*presenter.TokenResponse, error) {
+	ctx, span := tracing.Start(ctx, "customerUseCase.ChangePassword")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		u.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+	tk, ok := utils.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	cr, err := u.repo.GetIDCustomer(ctx, tk.ID)
+	if err != nil {
+		u.logger.WithContext(ctx).Errorf("error getting customer: %v", err)
+		return nil, err
+	}
+	if err := u.guard.Check(ctx, cr.Email, req.ClientIP); err != nil {
+		return nil, err
+	}
+	ok, _, err = u.hasher.Verify(req.CurrentPassword, cr.Password)
+	if err != nil {
+		u.logger.WithContext(ctx).Errorf("error verifying password: %v", err)
+		return nil, err
+	}
+	if !ok {
+		if err := u.guard.Fail(ctx, cr.Email, req.ClientIP); err != nil {
+			u.logger.WithContext(ctx).Errorf("error counting wrong current password: %v", err)
+		}
+		return nil, domain.ErrWrongPassword
+	}
+	if err := u.guard.Succeed(ctx, cr.Email, req.ClientIP); err != nil {
+		u.logger.WithContext(ctx).Errorf("error clearing failed sign-ins: %v", err)
+	}
+
+	if err := u.setPassword(ctx, cr, req.NewPassword); err != nil {
+		return nil, err
+	}
+	return u.tokens.Issue(ctx, cr)
+}
+
+// ForgotPassword implements CustomerUseCase. Only the request is checked
+// before answering: looking the email up and sending the reset happen in the
+// background, so neither the response nor its timing tells whether the email
+// belongs to a customer. When too many resets are already in flight the
+// request is dropped; the customer can ask again.
+func (u *customerUseCase) ForgotPassword(ctx context.Context, req presenter.ForgotPasswordRequest) error {
+	ctx, span := tracing.Start(ctx, "customerUseCase.ForgotPassword")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		u.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
+	}
+
+	select {
+	case u.resetSlots <- struct{}{}:
+	default:
+		u.logger.WithContext(ctx).Errorf("too many password resets in flight, dropping one")
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), resetSendTimeout)
+	go func() {
+		defer func() { <-u.resetSlots }()
+		defer cancel()
+		if err := u.sendReset(ctx, req.Email); err != nil {
+			u.logger.WithContext(ctx).Errorf("error sending password reset: %v", err)
+		}
+	}()
+	return nil
+}
+
+// sendReset mails a reset token to the customer owning email, if any.
+func (u *customerUseCase) sendReset(ctx context.Context, email string) error {
+	ctx, span := tracing.Start(ctx, "customerUseCase.sendReset")
+	defer span.End()
+
+	cr, err := u.repo.GetEmailCustomer(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	reset, plain, err := domain.NewPasswordReset(cr.ID, u.reset.TTL)
+	if err != nil {
+		return err
+	}
+	if err := u.resets.CreateReset(ctx, reset); err != nil {
+		return err
+	}
+
+	body := "Use this code to choose a new password: " + plain
+	if u.reset.URL != "" {
+		body = "Choose a new password at " + u.reset.URL + "?token=" + url.QueryEscape(plain)
+	}
+	return u.notifier.Notify(ctx, notify.Message{
+		To:      cr.Email,
+		Subject: "Reset your password",
+		Body:    body + "\nThe link expires in " + u.reset.TTL.String() + " and works once.",
+	})
+}
+
+// ResetPassword implements CustomerUseCase. The token is spent only when the
+// new password is accepted, and every session of the customer is revoked.
+func (u *customerUseCase) ResetPassword(ctx context.Context, req presenter.ResetPasswordRequest) error {
+	ctx, span := tracing.Start(ctx, "customerUseCase.ResetPassword")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		u.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
+	}
+
+	tokenHash := domain.HashResetToken(req.Token)
+	reset, err := u.resets.GetReset(ctx, tokenHash)
+	if err != nil {
+		return err
+	}
+	cr, err := u.repo.GetIDCustomer(ctx, reset.CustomerID)
+	if err != nil {
+		u.logger.WithContext(ctx).Errorf("error getting customer: %v", err)
+		return err
+	}
+	if err := u.policy.Check("new_password", req.NewPassword, cr.Email, cr.Name); err != nil {
+		return err
+	}
+
+	hash, err := u.hasher.Hash(req.NewPassword)
+	if err != nil {
+		u.logger.WithContext(ctx).Errorf("error hashing password: %v", err)
+		return err
+	}
+	if _, err := u.resets.RedeemReset(ctx, tokenHash, hash); err != nil {
+		return err
+	}
+
+	if err := u.tokens.RevokeCustomer(ctx, cr.ID); err != nil {
+		u.logger.WithContext(ctx).Errorf("error revoking tokens: %v", err)
+		return err
+	}
+	return nil
+}
+
+// setPassword checks newPassword against the policy and the current
+// password, stores its hash and revokes the customer's sessions.
+func (u *customerUseCase) setPassword(ctx context.Context, cr domain.Customer, newPassword string) error {
+	err := u.policy.Check("new_password", newPassword, cr.Email, cr.Name)
+	if same, _, _ := u.hasher.Verify(newPassword, cr.Password); same {
+		reuse := password.Violation{Field: "new_password", Rule: "reuse", Message: "must differ from the current password"}
+		var perr *password.PolicyError
+		if errors.As(err, &perr) {
+			perr.Violations = append(perr.Violations, reuse)
+		} else {
+			err = &password.PolicyError{Violations: []password.Violation{reuse}}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	hash, err := u.hasher.Hash(newPassword)
+	if err != nil {
+		u.logger.WithContext(ctx).Errorf("error hashing password: %v", err)
+		return err
+	}
+	err = u.repo.UpdatePasswordCustomer(ctx, domain.Customer{Email: cr.Email, Password: hash})
+	if err != nil {
+		u.logger.WithContext(ctx).Errorf("error updating password: %v", err)
+		return err
+	}
+
+	// A new password ends every session opened with the old one.
+	if err := u.tokens.RevokeCustomer(ctx, cr.ID); err != nil {
+		u.logger.WithContext(ctx).Errorf("error revoking tokens: %v", err)
+		return err
+	}
+	return nil
+}
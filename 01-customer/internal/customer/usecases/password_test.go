@@ -0,0 +1,312 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/customer/repositories"
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	tokens "github.com/adilsonmenechini/golabbank/customer/internal/token/usecases"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/loginguard"
+	"github.com/adilsonmenechini/golabbank/platform/notify"
+	"github.com/adilsonmenechini/golabbank/platform/password"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	currentPassword = "Current-passw0rd"
+	newPassword     = "Another-passw0rd"
+)
+
+// fakeCustomers keeps customers in memory. While gate is open, email
+// lookups wait for it to be closed.
+type fakeCustomers struct {
+	repositories.CustomerRepository
+	mu        sync.Mutex
+	customers map[string]domain.Customer
+	gate      chan struct{}
+}
+
+func (f *fakeCustomers) GetEmailCustomer(ctx context.Context, email string) (domain.Customer, error) {
+	if f.gate != nil {
+		<-f.gate
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.customers {
+		if c.Email == email {
+			return c, nil
+		}
+	}
+	return domain.Customer{}, sql.ErrNoRows
+}
+
+func (f *fakeCustomers) GetIDCustomer(ctx context.Context, id string) (domain.Customer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.customers[id]
+	if !ok {
+		return domain.Customer{}, sql.ErrNoRows
+	}
+	return c, nil
+}
+
+func (f *fakeCustomers) UpdatePasswordCustomer(ctx context.Context, customer domain.Customer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, c := range f.customers {
+		if c.Email == customer.Email {
+			c.Password = customer.Password
+			f.customers[id] = c
+		}
+	}
+	return nil
+}
+
+func (f *fakeCustomers) password(id string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.customers[id].Password
+}
+
+// fakeResets applies the rules of the SQL repository: one live reset per
+// customer, redeemed once, before it expires, together with the password.
+type fakeResets struct {
+	repositories.PasswordResetRepository
+	mu        sync.Mutex
+	resets    map[string]*domain.PasswordReset
+	customers *fakeCustomers
+}
+
+func (f *fakeResets) CreateReset(ctx context.Context, r *domain.PasswordReset) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for hash, old := range f.resets {
+		if old.CustomerID == r.CustomerID {
+			delete(f.resets, hash)
+		}
+	}
+	f.resets[r.TokenHash] = r
+	return nil
+}
+
+func (f *fakeResets) GetReset(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.resets[tokenHash]
+	if !ok || !r.Usable(time.Now().UTC()) {
+		return nil, domain.ErrResetTokenInvalid
+	}
+	return r, nil
+}
+
+func (f *fakeResets) RedeemReset(ctx context.Context, tokenHash, passwordHash string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.resets[tokenHash]
+	now := time.Now().UTC()
+	if !ok || !r.Usable(now) {
+		return "", domain.ErrResetTokenInvalid
+	}
+	r.UsedAt = &now
+	f.customers.mu.Lock()
+	c := f.customers.customers[r.CustomerID]
+	c.Password = passwordHash
+	f.customers.customers[r.CustomerID] = c
+	f.customers.mu.Unlock()
+	return r.CustomerID, nil
+}
+
+// fakeTokens counts the sessions revoked per customer.
+type fakeTokens struct {
+	tokens.TokenUseCase
+	mu      sync.Mutex
+	revoked map[string]int
+}
+
+func (f *fakeTokens) Issue(ctx context.Context, customer domain.Customer) (*presenter.TokenResponse, error) {
+	return &presenter.TokenResponse{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer"}, nil
+}
+
+func (f *fakeTokens) RevokeCustomer(ctx context.Context, customerID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.revoked[customerID]++
+	return nil
+}
+
+func (f *fakeTokens) revocations(customerID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.revoked[customerID]
+}
+
+// fakeNotifier hands every message to the test.
+type fakeNotifier struct {
+	sent chan notify.Message
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, msg notify.Message) error {
+	f.sent <- msg
+	return nil
+}
+
+type passwordFixture struct {
+	uc        CustomerUseCase
+	customers *fakeCustomers
+	tokens    *fakeTokens
+	mail      chan notify.Message
+	hasher    *password.Hasher
+}
+
+func newPasswordFixture(t *testing.T, ttl time.Duration) *passwordFixture {
+	t.Helper()
+	hasher := password.NewHasher(password.Argon2id{Memory: 64, Time: 1, Threads: 1}, password.Bcrypt{Cost: bcrypt.MinCost})
+	hash, err := hasher.Hash(currentPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	customers := &fakeCustomers{customers: map[string]domain.Customer{
+		"alice": domain.NewCustomer("alice", "Alice", "alice@example.com", hash),
+	}}
+	resets := &fakeResets{resets: make(map[string]*domain.PasswordReset), customers: customers}
+	tk := &fakeTokens{revoked: make(map[string]int)}
+	mail := make(chan notify.Message, 4)
+	policy := password.NewPolicy(password.Policy{MinLength: 12, RequireUpper: true, RequireLower: true, RequireDigit: true}, nil)
+	guard := loginguard.NewGuard(loginguard.NewMemoryStore(), loginguard.DefaultPolicy(), audit.NewLogAuditor())
+
+	uc := NewCustomerUseCase(customers, resets, tk, policy, hasher, guard, &fakeNotifier{sent: mail}, audit.NewLogAuditor(), PasswordResetConfig{TTL: ttl})
+	return &passwordFixture{uc: uc, customers: customers, tokens: tk, mail: mail, hasher: hasher}
+}
+
+// resetToken asks for a reset for email and returns the code mailed.
+func (f *passwordFixture) resetToken(t *testing.T, email string) string {
+	t.Helper()
+	if err := f.uc.ForgotPassword(context.Background(), presenter.ForgotPasswordRequest{Email: email}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-f.mail:
+		_, rest, _ := strings.Cut(msg.Body, ": ")
+		code, _, _ := strings.Cut(rest, "\n")
+		return code
+	case <-time.After(time.Second):
+		t.Fatal("no reset mailed")
+		return ""
+	}
+}
+
+func (f *passwordFixture) passwordIs(t *testing.T, plain string) bool {
+	t.Helper()
+	ok, _, err := f.hasher.Verify(plain, f.customers.password("alice"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+// TestResetTokenWorksOnce redeems a mailed token: the password changes,
+// every session is revoked and the token cannot be used again.
+func TestResetTokenWorksOnce(t *testing.T) {
+	f := newPasswordFixture(t, time.Hour)
+	ctx := context.Background()
+	token := f.resetToken(t, "alice@example.com")
+
+	if err := f.uc.ResetPassword(ctx, presenter.ResetPasswordRequest{Token: token, NewPassword: newPassword}); err != nil {
+		t.Fatal(err)
+	}
+	if !f.passwordIs(t, newPassword) {
+		t.Fatal("password was not changed")
+	}
+	if n := f.tokens.revocations("alice"); n != 1 {
+		t.Fatalf("sessions revoked %d times, want once", n)
+	}
+
+	err := f.uc.ResetPassword(ctx, presenter.ResetPasswordRequest{Token: token, NewPassword: "Third-passw0rd!"})
+	if !errors.Is(err, domain.ErrResetTokenInvalid) {
+		t.Fatalf("second use: err = %v, want ErrResetTokenInvalid", err)
+	}
+	if !f.passwordIs(t, newPassword) {
+		t.Fatal("a spent token changed the password")
+	}
+}
+
+func TestResetTokenExpires(t *testing.T) {
+	f := newPasswordFixture(t, 20*time.Millisecond)
+	token := f.resetToken(t, "alice@example.com")
+	time.Sleep(30 * time.Millisecond)
+
+	err := f.uc.ResetPassword(context.Background(), presenter.ResetPasswordRequest{Token: token, NewPassword: newPassword})
+	if !errors.Is(err, domain.ErrResetTokenInvalid) {
+		t.Fatalf("expired token: err = %v, want ErrResetTokenInvalid", err)
+	}
+	if !f.passwordIs(t, currentPassword) || f.tokens.revocations("alice") != 0 {
+		t.Fatal("an expired token changed the password or revoked sessions")
+	}
+}
+
+// TestForgotPasswordAnswersAlike holds every email lookup back: both
+// requests must still be answered, alike, so the answer cannot depend on
+// whether the email is registered.
+func TestForgotPasswordAnswersAlike(t *testing.T) {
+	f := newPasswordFixture(t, time.Hour)
+	f.customers.gate = make(chan struct{})
+	ctx := context.Background()
+
+	for _, email := range []string{"alice@example.com", "nobody@example.com"} {
+		answered := make(chan error, 1)
+		go func() { answered <- f.uc.ForgotPassword(ctx, presenter.ForgotPasswordRequest{Email: email}) }()
+		select {
+		case err := <-answered:
+			if err != nil {
+				t.Fatalf("%s: %v", email, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: the answer waited for the email lookup", email)
+		}
+	}
+
+	close(f.customers.gate)
+	select {
+	case msg := <-f.mail:
+		if msg.To != "alice@example.com" {
+			t.Fatalf("reset mailed to %s", msg.To)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no reset mailed to the registered email")
+	}
+	select {
+	case msg := <-f.mail:
+		t.Fatalf("reset mailed to %s, which is not registered", msg.To)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestChangePasswordChecksTheCurrentOne(t *testing.T) {
+	f := newPasswordFixture(t, time.Hour)
+	ctx := utils.ContextWithClaims(context.Background(), utils.Claims{ID: "alice"})
+
+	_, err := f.uc.ChangePassword(ctx, presenter.ChangePasswordRequest{CurrentPassword: "Wrong-passw0rd", NewPassword: newPassword})
+	if !errors.Is(err, domain.ErrWrongPassword) {
+		t.Fatalf("wrong current password: err = %v, want ErrWrongPassword", err)
+	}
+	if !f.passwordIs(t, currentPassword) || f.tokens.revocations("alice") != 0 {
+		t.Fatal("a wrong current password changed the password or revoked sessions")
+	}
+
+	res, err := f.uc.ChangePassword(ctx, presenter.ChangePasswordRequest{CurrentPassword: currentPassword, NewPassword: newPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AccessToken == "" || !f.passwordIs(t, newPassword) || f.tokens.revocations("alice") != 1 {
+		t.Fatal("changing the password did not store it, revoke the sessions and issue new tokens")
+	}
+}
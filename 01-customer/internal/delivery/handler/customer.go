@@ -3,122 +3,245 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"time"
+	"strconv"
 
-	"github.com/adilsonmenechini/golabbank/internal/customer/usecases"
-	"github.com/adilsonmenechini/golabbank/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/customer/internal/customer/usecases"
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/presenter"
+	tokens "github.com/adilsonmenechini/golabbank/customer/internal/token/usecases"
 
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/gorilla/mux"
 )
 
 type (
 	customerHandler struct {
 		logger *utils.Logger
 		pa     *presenter.CustomerPresenter
+		rs     *response.Presenter
 		us     usecases.CustomerUseCase
+		tk     tokens.TokenUseCase
 	}
 	CustomerHandler interface {
 		SignupHandler(w http.ResponseWriter, r *http.Request)
 		SigninHandler(w http.ResponseWriter, r *http.Request)
 		AuthorizeCustomerHandler(w http.ResponseWriter, r *http.Request)
 		LogoutHandler(w http.ResponseWriter, r *http.Request)
+		RefreshHandler(w http.ResponseWriter, r *http.Request)
+		ChangePasswordHandler(w http.ResponseWriter, r *http.Request)
+		ForgotPasswordHandler(w http.ResponseWriter, r *http.Request)
+		ResetPasswordHandler(w http.ResponseWriter, r *http.Request)
+		ListCustomersHandler(w http.ResponseWriter, r *http.Request)
+		SetRoleHandler(w http.ResponseWriter, r *http.Request)
 	}
 )
 
-func NewCustomerHandler(usa usecases.CustomerUseCase) CustomerHandler {
+func NewCustomerHandler(usa usecases.CustomerUseCase, tokenUC tokens.TokenUseCase) CustomerHandler {
 	return &customerHandler{
-		logger: utils.NewLogger("Handler"),
+		logger: utils.NewLogger("CustomerHandler"),
 		us:     usa,
+		tk:     tokenUC,
+		rs:     response.NewPresenter(),
 		pa:     presenter.NewCustomerPresenter(),
 	}
 }
 
-func (h *customerHandler) SignupHandler(w http.ResponseWriter, r *http.Request) {
+func (hc *customerHandler) SignupHandler(w http.ResponseWriter, r *http.Request) {
 	var req presenter.SignupRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		h.pa.CustomerError(w, http.StatusBadRequest, err.Error())
+		hc.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 		return
 	}
-	_, err = h.us.FindByEmail(r.Context(), req.Email)
+	_, err = hc.us.FindByEmail(r.Context(), req.Email)
 	if err == nil {
-		h.pa.CustomerError(w, http.StatusUnauthorized, "email already exists")
+		hc.rs.ResponseError(w, http.StatusUnauthorized, "email already exists")
 		return
 	}
 
-	err = h.us.Create(r.Context(), req)
+	err = hc.us.Create(r.Context(), req)
 	if err != nil {
-		h.pa.CustomerError(w, http.StatusBadRequest, err.Error())
+		respondError(hc.rs, w, err)
 		return
 	}
 
-	h.pa.CustomerSuccess(w, http.StatusCreated, "Customer created successfully")
+	hc.rs.ResponseSuccess(w, http.StatusCreated, "Customer created successfully")
 }
 
-func (h *customerHandler) SigninHandler(w http.ResponseWriter, r *http.Request) {
+func (hc *customerHandler) SigninHandler(w http.ResponseWriter, r *http.Request) {
 	var req presenter.SigninRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		h.pa.CustomerError(w, http.StatusBadRequest, err.Error())
+		hc.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 		return
 	}
+	req.ClientIP = utils.ClientIP(r)
 
-	input, err := h.us.FindByEmail(r.Context(), req.Email)
+	input, err := hc.us.Authenticate(r.Context(), req)
 	if err != nil {
-		h.pa.CustomerError(w, http.StatusUnauthorized, "email or password incorrect")
+		respondError(hc.rs, w, err)
 		return
 	}
-	check := utils.CheckPasswordHash(req.Password, input.Password)
 
-	if !check {
-		h.pa.CustomerError(w, http.StatusUnauthorized, "email or password incorrect")
+	res, err := hc.tk.Issue(r.Context(), input)
+	if err != nil {
+		respondError(hc.rs, w, err)
 		return
 	}
 
-	jwtg, err := utils.GenerateJWT(input.ID, input.Email)
+	utils.SetTokenAuthorization(w, res.AccessToken)
+
+	hc.rs.ResponseData(w, http.StatusOK, res)
+
+}
+
+func (hc *customerHandler) AuthorizeCustomerHandler(w http.ResponseWriter, r *http.Request) {
+
+	tk, err := utils.GetTokenAuthorization(r)
 	if err != nil {
-		h.pa.CustomerError(w, http.StatusInternalServerError, err.Error())
+		hc.rs.ResponseErrorToken(w, http.StatusUnauthorized, "invalid token")
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:  "token",
-		Value: jwtg,
-	})
-
-	h.pa.CustomerSuccess(w, http.StatusOK, "login successful")
+	hc.rs.ResponseSuccess(w, http.StatusOK, "welcome "+tk.Email)
 
 }
 
-func (h *customerHandler) AuthorizeCustomerHandler(w http.ResponseWriter, r *http.Request) {
+func (hc *customerHandler) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	var req presenter.RefreshRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hc.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
 
-	tk, err := r.Cookie("token")
+	res, err := hc.tk.Refresh(r.Context(), req)
 	if err != nil {
-		if err == http.ErrNoCookie {
-			w.WriteHeader(http.StatusUnauthorized)
+		respondError(hc.rs, w, err)
+		return
+	}
+
+	utils.SetTokenAuthorization(w, res.AccessToken)
+
+	hc.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// LogoutHandler revokes the access token the request was made with and,
+// when the body carries one, the refresh token of the same sign-in.
+func (hc *customerHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	var req presenter.LogoutRequest
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			hc.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 			return
 		}
-		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	err := hc.tk.Logout(r.Context(), req)
+	if err != nil {
+		respondError(hc.rs, w, err)
+		return
+	}
+
+	hc.rs.ResponseSuccess(w, http.StatusOK, "logout successful")
+}
+
+// ChangePasswordHandler replaces the caller's password and returns a new
+// token pair; every other session is signed out.
+func (hc *customerHandler) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req presenter.ChangePasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hc.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 		return
 	}
+	req.ClientIP = utils.ClientIP(r)
 
-	acc, err := utils.ValidateToken(tk.Value)
+	res, err := hc.us.ChangePassword(r.Context(), req)
 	if err != nil {
-		h.pa.CustomerErrorToken(w, http.StatusUnauthorized, "invalid token")
+		respondError(hc.rs, w, err)
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:  "token",
-		Value: tk.Value,
-	})
-	h.pa.CustomerSuccess(w, http.StatusOK, "welcome "+acc)
+	utils.SetTokenAuthorization(w, res.AccessToken)
 
+	hc.rs.ResponseData(w, http.StatusOK, res)
 }
 
-func (h *customerHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
-	http.SetCookie(w, &http.Cookie{
-		Name:    "token",
-		Expires: time.Now(),
-	})
+// ForgotPasswordHandler sends a reset token to the customer's email. Unknown
+// emails get the same 202 so callers cannot probe which are registered.
+func (hc *customerHandler) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req presenter.ForgotPasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hc.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
+
+	if err := hc.us.ForgotPassword(r.Context(), req); err != nil {
+		respondError(hc.rs, w, err)
+		return
+	}
+
+	hc.rs.ResponseSuccess(w, http.StatusAccepted, "if the email is registered, a reset link is on its way")
+}
+
+func (hc *customerHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req presenter.ResetPasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hc.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
+
+	if err := hc.us.ResetPassword(r.Context(), req); err != nil {
+		respondError(hc.rs, w, err)
+		return
+	}
+
+	hc.rs.ResponseSuccess(w, http.StatusOK, "password reset")
+}
+
+// ListCustomersHandler implements CustomerHandler.
+func (hc *customerHandler) ListCustomersHandler(w http.ResponseWriter, r *http.Request) {
+	var req presenter.ListCustomersRequest
+	q := r.URL.Query()
+	for name, dst := range map[string]*int{"limit": &req.Limit, "offset": &req.Offset} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				hc.rs.ResponseError(w, http.StatusBadRequest, "invalid "+name)
+				return
+			}
+			*dst = n
+		}
+	}
+
+	res, err := hc.us.ListCustomers(r.Context(), req)
+	if err != nil {
+		respondError(hc.rs, w, err)
+		return
+	}
+
+	hc.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// SetRoleHandler implements CustomerHandler.
+func (hc *customerHandler) SetRoleHandler(w http.ResponseWriter, r *http.Request) {
+	var req presenter.SetRoleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hc.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
+	req.CustomerID = mux.Vars(r)["customer_id"]
+
+	res, err := hc.us.SetRole(r.Context(), req)
+	if err != nil {
+		respondError(hc.rs, w, err)
+		return
+	}
+
+	hc.rs.ResponseData(w, http.StatusOK, res)
 }
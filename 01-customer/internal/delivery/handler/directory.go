@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/customer/usecases"
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	tokens "github.com/adilsonmenechini/golabbank/customer/internal/token/usecases"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+type (
+	directoryHandler struct {
+		logger *utils.Logger
+		rs     *response.Presenter
+		us     usecases.CustomerUseCase
+		tk     tokens.TokenUseCase
+	}
+	// DirectoryHandler serves the identity lookups other services make on
+	// behalf of their callers.
+	DirectoryHandler interface {
+		LookupCustomerHandler(w http.ResponseWriter, r *http.Request)
+		CheckTokenHandler(w http.ResponseWriter, r *http.Request)
+	}
+)
+
+func NewDirectoryHandler(usa usecases.CustomerUseCase, tokenUC tokens.TokenUseCase) DirectoryHandler {
+	return &directoryHandler{
+		logger: utils.NewLogger("DirectoryHandler"),
+		rs:     response.NewPresenter(),
+		us:     usa,
+		tk:     tokenUC,
+	}
+}
+
+// LookupCustomerHandler returns a customer's public profile.
+func (hd *directoryHandler) LookupCustomerHandler(w http.ResponseWriter, r *http.Request) {
+	cr, err := hd.us.FindByID(r.Context(), mux.Vars(r)["customer_id"])
+	if err != nil {
+		respondError(hd.rs, w, err)
+		return
+	}
+
+	hd.rs.ResponseData(w, http.StatusOK, presenter.NewCustomerResponse(cr))
+}
+
+// CheckTokenHandler reports whether an access token has been revoked by a
+// logout, a password change or a refresh token reuse.
+func (hd *directoryHandler) CheckTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var req presenter.TokenCheckRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hd.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
+	if err := utils.ValidateStruct(req); err != nil {
+		respondError(hd.rs, w, err)
+		return
+	}
+
+	claims := utils.Claims{
+		ID: req.CustomerID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       req.JTI,
+			IssuedAt: jwt.NewNumericDate(time.UnixMicro(req.IssuedAtMicros)),
+		},
+	}
+	err = hd.tk.Check(r.Context(), claims)
+	if err != nil && !errors.Is(err, domain.ErrTokenRevoked) {
+		hd.logger.WithContext(r.Context()).Errorf("error checking token: %v", err)
+		hd.rs.ResponseError(w, http.StatusServiceUnavailable, "Service Unavailable")
+		return
+	}
+
+	hd.rs.ResponseData(w, http.StatusOK, presenter.TokenCheckResponse{Revoked: err != nil})
+}
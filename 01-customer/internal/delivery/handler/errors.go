@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/identity"
+	"github.com/adilsonmenechini/golabbank/platform/loginguard"
+	"github.com/adilsonmenechini/golabbank/platform/password"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/asaskevich/govalidator"
+)
+
+// invalidBody answers a request whose JSON body could not be decoded.
+const invalidBody = "invalid request body"
+
+// statusFor maps a usecase error to the HTTP status returned to the client.
+// Anything not listed here is an unexpected failure, such as a lost database
+// connection, and is reported as 500.
+func statusFor(err error) int {
+	var verr govalidator.Errors
+	switch {
+	case errors.As(err, &verr):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUnauthenticated),
+		errors.Is(err, domain.ErrInvalidCredentials),
+		errors.Is(err, domain.ErrRefreshTokenInvalid),
+		errors.Is(err, domain.ErrRefreshTokenReused),
+		errors.Is(err, domain.ErrTokenRevoked),
+		errors.Is(err, domain.ErrResetTokenInvalid):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrWrongPassword),
+		errors.Is(err, domain.ErrOwnRole):
+		return http.StatusForbidden
+	case errors.Is(err, sql.ErrNoRows),
+		errors.Is(err, domain.ErrCustomerNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, password.ErrPolicy),
+		errors.Is(err, identity.ErrUnknownRole):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, loginguard.ErrThrottled):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondError writes err with the status from statusFor, listing the
+// individual rules when a password was refused by the policy and telling a
+// throttled client when to retry. Server-side failures only report their
+// status text: their messages describe internals, not the request.
+func respondError(rs *response.Presenter, w http.ResponseWriter, err error) {
+	var perr *password.PolicyError
+	if errors.As(err, &perr) {
+		rs.ResponseViolations(w, http.StatusUnprocessableEntity, password.ErrPolicy.Error(), perr.Violations)
+		return
+	}
+	var terr *loginguard.ThrottledError
+	if errors.As(err, &terr) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(terr.RetryAfter.Seconds()))))
+	}
+	status := statusFor(err)
+	if status >= http.StatusInternalServerError {
+		rs.ResponseError(w, status, http.StatusText(status))
+		return
+	}
+	rs.ResponseError(w, status, err.Error())
+}
@@ -0,0 +1,107 @@
+package presenter
+
+import (
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type SignupRequest struct {
+	Name     string `json:"name" valid:"notnull"`
+	Email    string `json:"email" valid:"notnull,email"`
+	Password string `json:"password" valid:"notnull"`
+}
+
+type SigninRequest struct {
+	Email    string `json:"email" valid:"notnull,email"`
+	Password string `json:"password" valid:"notnull"`
+	// ClientIP is filled in by the handler, never from the body.
+	ClientIP string `json:"-" valid:"-"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" valid:"notnull"`
+	NewPassword     string `json:"new_password" valid:"notnull"`
+	// ClientIP is filled in by the handler, never from the body.
+	ClientIP string `json:"-" valid:"-"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" valid:"notnull,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" valid:"notnull"`
+	NewPassword string `json:"new_password" valid:"notnull"`
+}
+
+// ListCustomersRequest pages through all customers, oldest first.
+type ListCustomersRequest struct {
+	Limit  int `json:"limit" valid:"optional"`
+	Offset int `json:"offset" valid:"optional"`
+}
+
+type SetRoleRequest struct {
+	CustomerID string `json:"customer_id" valid:"notnull"`
+	Role       string `json:"role" valid:"notnull"`
+}
+
+type CustomerResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewCustomerResponse(cr domain.Customer) CustomerResponse {
+	return CustomerResponse{
+		ID:        cr.ID,
+		Name:      cr.Name,
+		Email:     cr.Email,
+		Role:      string(cr.Role),
+		CreatedAt: cr.CreatedAt,
+	}
+}
+
+type CustomersToken struct {
+	Token string `json:"token" valid:"notnull,email"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" valid:"notnull"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" valid:"optional"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type CustomerPresenter struct {
+	logger *utils.Logger
+}
+
+func NewCustomerPresenter() *CustomerPresenter {
+	return &CustomerPresenter{
+		logger: utils.NewLogger("presenter"),
+	}
+}
+
+// TokenCheckRequest asks whether an access token, identified by its jti,
+// subject and issue time (Unix microseconds), has been revoked.
+type TokenCheckRequest struct {
+	JTI            string `json:"jti" valid:"notnull"`
+	CustomerID     string `json:"customer_id" valid:"notnull"`
+	IssuedAtMicros int64  `json:"issued_at_us" valid:"required"`
+}
+
+type TokenCheckResponse struct {
+	Revoked bool `json:"revoked"`
+}
@@ -0,0 +1,75 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	tokens "github.com/adilsonmenechini/golabbank/customer/internal/token/usecases"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/rbac"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// authenticator validates bearer tokens and consults the revocation lists
+// before letting a request through.
+type authenticator struct {
+	tokens tokens.TokenUseCase
+	roles  *rbac.Enforcer
+	rs     *response.Presenter
+	logger *utils.Logger
+}
+
+func newAuthenticator(tokenUC tokens.TokenUseCase) *authenticator {
+	return &authenticator{
+		tokens: tokenUC,
+		roles:  rbac.NewEnforcer(audit.NewLogAuditor()),
+		rs:     response.NewPresenter(),
+		logger: utils.NewLogger("Auth"),
+	}
+}
+
+func (au *authenticator) jwtMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tk, err := utils.GetTokenAuthorization(r)
+		if err != nil {
+			au.rs.ResponseError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if err := au.tokens.Check(r.Context(), tk); err != nil {
+			if errors.Is(err, domain.ErrTokenRevoked) {
+				au.rs.ResponseError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+			au.logger.Errorf("error checking token revocation: %v", err)
+			au.rs.ResponseError(w, http.StatusServiceUnavailable, "Service Unavailable")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(utils.ContextWithClaims(r.Context(), tk)))
+	})
+}
+
+// sweep deletes expired refresh tokens and denylist entries every interval
+// until ctx is done.
+func (au *authenticator) sweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := au.tokens.Purge(ctx)
+			if err != nil {
+				au.logger.Errorf("error deleting expired tokens: %v", err)
+				continue
+			}
+			if n > 0 {
+				au.logger.Infof("deleted %d expired tokens", n)
+			}
+		}
+	}
+}
@@ -1,30 +1,54 @@
 package router
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
 	"net/http"
 	"time"
 
-	"github.com/adilsonmenechini/golabbank/internal/delivery/handler"
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/handler"
+	tokens "github.com/adilsonmenechini/golabbank/customer/internal/token/usecases"
+	"github.com/adilsonmenechini/golabbank/platform/identity"
+	"github.com/adilsonmenechini/golabbank/platform/metrics"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
 	"github.com/gorilla/mux"
 )
 
 type CustomerRouter struct {
 	hdl    handler.CustomerHandler
+	dir    handler.DirectoryHandler
+	auth   *authenticator
 	logger *utils.Logger
 }
 
-func NewCustomerRouter(hdlr handler.CustomerHandler) *CustomerRouter {
+func NewCustomerRouter(hdlr handler.CustomerHandler, dir handler.DirectoryHandler, tokenUC tokens.TokenUseCase) *CustomerRouter {
 	return &CustomerRouter{
 		hdl:    hdlr,
+		dir:    dir,
+		auth:   newAuthenticator(tokenUC),
 		logger: utils.NewLogger("Router"),
 	}
 }
 
-func (ra *CustomerRouter) Router() {
+// Router builds the handler of the customer API. Expired tokens are swept
+// until ctx is done.
+func (ra *CustomerRouter) Router(ctx context.Context) (http.Handler, error) {
+	keys, err := utils.TokenKeys()
+	if err != nil {
+		return nil, fmt.Errorf("loading token keys: %w", err)
+	}
+	go ra.auth.sweep(ctx, time.Hour)
+
 	r := mux.NewRouter()
+	// Public keys other services use to verify our access tokens.
+	r.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		json.NewEncoder(w).Encode(keys.JWKS())
+	}).Methods("GET")
+
 	s := r.PathPrefix("/api/v1").Subrouter()
 
 	s.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -34,16 +58,26 @@ func (ra *CustomerRouter) Router() {
 	a := s.PathPrefix("/customer").Subrouter()
 	a.HandleFunc("/signin", ra.hdl.SigninHandler).Methods("GET")
 	a.HandleFunc("/signup", ra.hdl.SignupHandler).Methods("POST")
-	a.HandleFunc("/welcome", ra.hdl.AuthorizeCustomerHandler).Methods("GET")
-	a.HandleFunc("/logout", ra.hdl.LogoutHandler).Methods("GET")
-
-	ra.logger.Info("Servidor rodando na porta :8000")
-	srv := &http.Server{
-		Handler: r,
-		Addr:    "127.0.0.1:8000",
-		// Good practice: enforce timeouts for servers you create!
-		WriteTimeout: 15 * time.Second,
-		ReadTimeout:  15 * time.Second,
-	}
-	log.Fatal(srv.ListenAndServe())
+	a.HandleFunc("/refresh", ra.hdl.RefreshHandler).Methods("POST")
+	a.Handle("/welcome", ra.auth.jwtMiddleware(http.HandlerFunc(ra.hdl.AuthorizeCustomerHandler))).Methods("GET")
+	a.Handle("/logout", ra.auth.jwtMiddleware(http.HandlerFunc(ra.hdl.LogoutHandler))).Methods("POST")
+	a.Handle("/password", ra.auth.jwtMiddleware(http.HandlerFunc(ra.hdl.ChangePasswordHandler))).Methods("PUT")
+	a.HandleFunc("/password/forgot", ra.hdl.ForgotPasswordHandler).Methods("POST")
+	a.HandleFunc("/password/reset", ra.hdl.ResetPasswordHandler).Methods("POST")
+
+	// Back office. Every route declares the roles allowed on it.
+	adm := a.PathPrefix("/admin").Subrouter()
+	adm.Handle("/customers", ra.auth.roles.Allow(ra.hdl.ListCustomersHandler, identity.RoleAdmin, identity.RoleAuditor)).Methods("GET")
+	adm.Handle("/customers/{customer_id}/role", ra.auth.roles.Allow(ra.hdl.SetRoleHandler, identity.RoleAdmin)).Methods("PUT")
+	adm.Use(ra.auth.jwtMiddleware)
+
+	// Identity lookups for the account service.
+	d := a.PathPrefix("/directory").Subrouter()
+	d.HandleFunc("/customers/{customer_id}", ra.dir.LookupCustomerHandler).Methods("GET")
+	d.HandleFunc("/tokens/check", ra.dir.CheckTokenHandler).Methods("POST")
+	d.Use(serviceMiddleware)
+	r.Use(metrics.Route, tracing.Route)
+	r.Handle("/metrics", metrics.Handler()).Methods("GET")
+
+	return utils.RequestID(r), nil
 }
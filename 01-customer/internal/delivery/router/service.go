@@ -0,0 +1,27 @@
+package router
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/adilsonmenechini/golabbank/platform/response"
+)
+
+const serviceKeyHeader = "X-Service-Key"
+
+// serviceMiddleware restricts the directory routes to the other bank services,
+// which present the SERVICE_API_KEY shared secret. When no key is configured
+// every request is refused.
+func serviceMiddleware(next http.Handler) http.Handler {
+	rs := response.NewPresenter()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := os.Getenv("SERVICE_API_KEY")
+		got := r.Header.Get(serviceKeyHeader)
+		if want == "" || subtle.ConstantTimeCompare([]byte(want), []byte(got)) != 1 {
+			rs.ResponseError(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
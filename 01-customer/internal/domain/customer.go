@@ -1,26 +1,21 @@
 package domain
 
 import (
-	"time"
+	"errors"
 
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"github.com/adilsonmenechini/golabbank/platform/identity"
 )
 
-type Customer struct {
-	ID        string
-	Name      string
-	Email     string
-	Password  string
-	CreatedAt time.Time
-}
+var (
+	ErrUnauthenticated    = errors.New("authentication required")
+	ErrInvalidCredentials = errors.New("email or password incorrect")
+	ErrCustomerNotFound   = identity.ErrCustomerNotFound
+	ErrOwnRole            = errors.New("admins cannot change their own role")
+)
+
+// Customer is the customer record shared with the account service.
+type Customer = identity.Customer
 
-func NewCustomer(id, name, email, password string) Customer {
-	pwd := utils.HashPassword(password)
-	return Customer{
-		ID:        id,
-		Name:      name,
-		Email:     email,
-		Password:  pwd,
-		CreatedAt: time.Now().UTC(),
-	}
+func NewCustomer(id, name, email, passwordHash string) Customer {
+	return identity.NewCustomer(id, name, email, passwordHash)
 }
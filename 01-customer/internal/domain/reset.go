@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+var (
+	ErrResetTokenInvalid = errors.New("invalid or expired password reset token")
+	ErrWrongPassword     = errors.New("current password is incorrect")
+)
+
+// PasswordReset is a single-use credential mailed to a customer who forgot
+// their password. Only its SHA-256 hash is stored.
+type PasswordReset struct {
+	ID         string
+	CustomerID string
+	TokenHash  string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	UsedAt     *time.Time
+}
+
+// NewPasswordReset creates a reset for customerID valid for ttl. It returns
+// the reset to store and its plain token, which is only ever sent to the
+// customer.
+func NewPasswordReset(customerID string, ttl time.Duration) (*PasswordReset, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	plain := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now().UTC()
+	return &PasswordReset{
+		ID:         utils.GenerateUUID(),
+		CustomerID: customerID,
+		TokenHash:  HashResetToken(plain),
+		ExpiresAt:  now.Add(ttl),
+		CreatedAt:  now,
+	}, plain, nil
+}
+
+// HashResetToken returns the lookup hash of a plain reset token.
+func HashResetToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// Usable reports whether the reset can still be redeemed at now.
+func (r *PasswordReset) Usable(now time.Time) bool {
+	return r.UsedAt == nil && now.Before(r.ExpiresAt)
+}
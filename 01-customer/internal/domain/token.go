@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected; all sessions of this login were revoked")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+)
+
+// RefreshToken is an opaque, single-use credential exchanged for a new access
+// token. Only its SHA-256 hash is stored. Every refresh token descends from a
+// sign-in through FamilyID, so presenting a token that was already used
+// revokes every token of that sign-in.
+type RefreshToken struct {
+	ID         string
+	FamilyID   string
+	CustomerID string
+	TokenHash  string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	UsedAt     *time.Time
+	RevokedAt  *time.Time
+}
+
+// NewRefreshToken creates a token in familyID, starting a new family when
+// familyID is empty. It returns the token to store and its plain value, which
+// is only ever handed to the client.
+func NewRefreshToken(customerID, familyID string, ttl time.Duration) (*RefreshToken, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	plain := base64.RawURLEncoding.EncodeToString(b)
+
+	if familyID == "" {
+		familyID = utils.GenerateUUID()
+	}
+	now := time.Now().UTC()
+	return &RefreshToken{
+		ID:         utils.GenerateUUID(),
+		FamilyID:   familyID,
+		CustomerID: customerID,
+		TokenHash:  HashRefreshToken(plain),
+		ExpiresAt:  now.Add(ttl),
+		CreatedAt:  now,
+	}, plain, nil
+}
+
+// HashRefreshToken returns the lookup hash of a plain refresh token. The
+// tokens are 256 random bits, so a fast unsalted hash is enough.
+func HashRefreshToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// Usable reports whether the token can still be exchanged at now.
+func (t *RefreshToken) Usable(now time.Time) bool {
+	return t.UsedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// Spent reports whether the token was already exchanged or revoked, which
+// means whoever presents it again is replaying it.
+func (t *RefreshToken) Spent() bool {
+	return t.UsedAt != nil || t.RevokedAt != nil
+}
+
+// Redeem reports whether t can be exchanged for a new token at now:
+// ErrRefreshTokenReused when it is spent, in which case its family must be
+// revoked, and ErrRefreshTokenInvalid when it expired.
+func (t *RefreshToken) Redeem(now time.Time) error {
+	if t.Spent() {
+		return ErrRefreshTokenReused
+	}
+	if !t.Usable(now) {
+		return ErrRefreshTokenInvalid
+	}
+	return nil
+}
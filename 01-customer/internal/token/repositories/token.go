@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type (
+	TokenRepository interface {
+		CreateRefreshToken(ctx context.Context, t *domain.RefreshToken) error
+		RotateRefreshToken(ctx context.Context, tokenHash string, next func(cur *domain.RefreshToken) (*domain.RefreshToken, error)) (*domain.RefreshToken, error)
+		RevokeFamily(ctx context.Context, familyID string) error
+		RevokeRefreshToken(ctx context.Context, tokenHash, customerID string) error
+		RevokeCustomer(ctx context.Context, customerID string, notBefore time.Time) error
+		DenyAccessToken(ctx context.Context, jti, customerID string, expiresAt time.Time) error
+		IsAccessTokenRevoked(ctx context.Context, jti, customerID string, issuedAt time.Time) (bool, error)
+		DeleteExpired(ctx context.Context) (int64, error)
+	}
+
+	tokenRepository struct {
+		logger *utils.Logger
+		db     *sql.DB
+	}
+)
+
+// CreateRefreshToken implements TokenRepository.
+func (tr *tokenRepository) CreateRefreshToken(ctx context.Context, t *domain.RefreshToken) error {
+	return createRefreshToken(ctx, tr.db, t)
+}
+
+// RotateRefreshToken implements TokenRepository. The presented token is
+// locked and marked used, and the token built by next is stored in its place,
+// all in one transaction. When the presented token had already been used or
+// revoked its whole family is revoked and ErrRefreshTokenReused returned;
+// that revocation is committed even though the call fails.
+func (tr *tokenRepository) RotateRefreshToken(ctx context.Context, tokenHash string, next func(cur *domain.RefreshToken) (*domain.RefreshToken, error)) (*domain.RefreshToken, error) {
+	tx, err := tr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cur, err := scanRefreshToken(tx.QueryRowContext(ctx, lockRefreshToken, tokenHash))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := cur.Redeem(now); errors.Is(err, domain.ErrRefreshTokenReused) {
+		if _, err := tx.ExecContext(ctx, revokeFamily, cur.FamilyID, now); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		tr.logger.WithContext(ctx).Warnf("refresh token reuse in family %s of customer %s", cur.FamilyID, cur.CustomerID)
+		return nil, domain.ErrRefreshTokenReused
+	} else if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, useRefreshToken, cur.ID, now); err != nil {
+		return nil, err
+	}
+	nt, err := next(cur)
+	if err != nil {
+		return nil, err
+	}
+	if err := createRefreshToken(ctx, tx, nt); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return nt, nil
+}
+
+// RevokeFamily implements TokenRepository.
+func (tr *tokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := tr.db.ExecContext(ctx, revokeFamily, familyID, time.Now().UTC())
+	return err
+}
+
+// RevokeRefreshToken implements TokenRepository. It revokes the family of the
+// given token, provided the token belongs to customerID.
+func (tr *tokenRepository) RevokeRefreshToken(ctx context.Context, tokenHash, customerID string) error {
+	_, err := tr.db.ExecContext(ctx, revokeFamilyOf, tokenHash, customerID, time.Now().UTC())
+	return err
+}
+
+// RevokeCustomer implements TokenRepository. Every refresh token of the
+// customer is revoked and access tokens issued before notBefore stop being
+// accepted.
+func (tr *tokenRepository) RevokeCustomer(ctx context.Context, customerID string, notBefore time.Time) error {
+	tx, err := tr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, revokeCustomerRefresh, customerID, time.Now().UTC()); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, upsertCutoff, customerID, notBefore.UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DenyAccessToken implements TokenRepository.
+func (tr *tokenRepository) DenyAccessToken(ctx context.Context, jti, customerID string, expiresAt time.Time) error {
+	_, err := tr.db.ExecContext(ctx, denyAccessToken, jti, customerID, expiresAt.UTC())
+	return err
+}
+
+// IsAccessTokenRevoked implements TokenRepository.
+func (tr *tokenRepository) IsAccessTokenRevoked(ctx context.Context, jti, customerID string, issuedAt time.Time) (bool, error) {
+	var revoked bool
+	err := tr.db.QueryRowContext(ctx, isAccessTokenRevoked, jti, customerID, issuedAt.UTC()).Scan(&revoked)
+	return revoked, err
+}
+
+// DeleteExpired implements TokenRepository. Denylist entries and refresh
+// tokens are useless once the token they describe has expired.
+func (tr *tokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	now := time.Now().UTC()
+	var total int64
+	for _, q := range []string{deleteExpiredDenied, deleteExpiredRefresh} {
+		res, err := tr.db.ExecContext(ctx, q, now)
+		if err != nil {
+			return total, err
+		}
+		n, _ := res.RowsAffected()
+		total += n
+	}
+	return total, nil
+}
+
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func createRefreshToken(ctx context.Context, db dbtx, t *domain.RefreshToken) error {
+	_, err := db.ExecContext(ctx, createRefresh,
+		t.ID,
+		t.FamilyID,
+		t.CustomerID,
+		t.TokenHash,
+		t.ExpiresAt,
+		t.CreatedAt,
+	)
+	return err
+}
+
+func scanRefreshToken(row *sql.Row) (*domain.RefreshToken, error) {
+	var (
+		i               domain.RefreshToken
+		usedAt, revoked sql.NullTime
+	)
+	err := row.Scan(
+		&i.ID,
+		&i.FamilyID,
+		&i.CustomerID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&usedAt,
+		&revoked,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		i.UsedAt = &usedAt.Time
+	}
+	if revoked.Valid {
+		i.RevokedAt = &revoked.Time
+	}
+	return &i, nil
+}
+
+func NewTokenRepository(DB *sql.DB) TokenRepository {
+	return &tokenRepository{
+		logger: utils.NewLogger("TokenRepository"),
+		db:     DB,
+	}
+}
+
+const (
+	createRefresh         = `INSERT INTO refresh_tokens (id, family_id, customer_id, token_hash, expires_at, created_at) VALUES ( $1, $2, $3, $4, $5, $6)`
+	lockRefreshToken      = `SELECT id, family_id, customer_id, token_hash, expires_at, created_at, used_at, revoked_at FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`
+	useRefreshToken       = `UPDATE refresh_tokens set used_at = $2 WHERE id = $1`
+	revokeFamily          = `UPDATE refresh_tokens set revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL`
+	revokeFamilyOf        = `UPDATE refresh_tokens set revoked_at = $3 WHERE revoked_at IS NULL AND family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1 AND customer_id = $2)`
+	revokeCustomerRefresh = `UPDATE refresh_tokens set revoked_at = $2 WHERE customer_id = $1 AND revoked_at IS NULL`
+	upsertCutoff          = `INSERT INTO token_cutoffs (customer_id, not_before) VALUES ($1, $2) ON CONFLICT (customer_id) DO UPDATE SET not_before = GREATEST(token_cutoffs.not_before, EXCLUDED.not_before)`
+	denyAccessToken       = `INSERT INTO revoked_tokens (jti, customer_id, expires_at) VALUES ($1, $2, $3) ON CONFLICT (jti) DO NOTHING`
+	isAccessTokenRevoked  = `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1) OR EXISTS (SELECT 1 FROM token_cutoffs WHERE customer_id = $2 AND not_before > $3)`
+	deleteExpiredDenied   = `DELETE FROM revoked_tokens WHERE expires_at < $1`
+	deleteExpiredRefresh  = `DELETE FROM refresh_tokens WHERE expires_at < $1`
+)
@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/customer/repositories"
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	tokenrepo "github.com/adilsonmenechini/golabbank/customer/internal/token/repositories"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type (
+	TokenUseCase interface {
+		Issue(ctx context.Context, customer domain.Customer) (*presenter.TokenResponse, error)
+		Refresh(ctx context.Context, req presenter.RefreshRequest) (*presenter.TokenResponse, error)
+		Logout(ctx context.Context, req presenter.LogoutRequest) error
+		RevokeCustomer(ctx context.Context, customerID string) error
+		Check(ctx context.Context, claims utils.Claims) error
+		Purge(ctx context.Context) (int64, error)
+	}
+
+	tokenUseCase struct {
+		logger     *utils.Logger
+		repo       tokenrepo.TokenRepository
+		customers  repositories.CustomerRepository
+		refreshTTL time.Duration
+	}
+)
+
+// Issue implements TokenUseCase. It starts a new refresh token family, one
+// per sign-in.
+func (tuc *tokenUseCase) Issue(ctx context.Context, customer domain.Customer) (*presenter.TokenResponse, error) {
+	rt, plain, err := domain.NewRefreshToken(customer.ID, "", tuc.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	if err := tuc.repo.CreateRefreshToken(ctx, rt); err != nil {
+		tuc.logger.WithContext(ctx).Errorf("error storing refresh token: %v", err)
+		return nil, err
+	}
+	return tuc.pair(customer, plain)
+}
+
+// Refresh implements TokenUseCase.
+func (tuc *tokenUseCase) Refresh(ctx context.Context, req presenter.RefreshRequest) (*presenter.TokenResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		tuc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	var plain string
+	next, err := tuc.repo.RotateRefreshToken(ctx, domain.HashRefreshToken(req.RefreshToken), func(cur *domain.RefreshToken) (*domain.RefreshToken, error) {
+		var (
+			nt  *domain.RefreshToken
+			err error
+		)
+		nt, plain, err = domain.NewRefreshToken(cur.CustomerID, cur.FamilyID, tuc.refreshTTL)
+		return nt, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := tuc.customers.GetIDCustomer(ctx, next.CustomerID)
+	if err != nil {
+		tuc.logger.WithContext(ctx).Errorf("error getting customer: %v", err)
+		return nil, err
+	}
+	return tuc.pair(customer, plain)
+}
+
+// Logout implements TokenUseCase. The caller's access token is denylisted
+// until it expires and, when a refresh token is given, its family is revoked.
+func (tuc *tokenUseCase) Logout(ctx context.Context, req presenter.LogoutRequest) error {
+	if err := utils.ValidateStruct(req); err != nil {
+		tuc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
+	}
+	tk, ok := utils.ClaimsFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthenticated
+	}
+
+	if tk.RegisteredClaims.ID != "" && tk.ExpiresAt != nil {
+		if err := tuc.repo.DenyAccessToken(ctx, tk.RegisteredClaims.ID, tk.ID, tk.ExpiresAt.Time); err != nil {
+			tuc.logger.WithContext(ctx).Errorf("error revoking access token: %v", err)
+			return err
+		}
+	}
+	if req.RefreshToken != "" {
+		if err := tuc.repo.RevokeRefreshToken(ctx, domain.HashRefreshToken(req.RefreshToken), tk.ID); err != nil {
+			tuc.logger.WithContext(ctx).Errorf("error revoking refresh token: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeCustomer implements TokenUseCase. Tokens carry their issue time to
+// the microsecond, so the cutoff ends every token signed before this call,
+// including earlier in the same second.
+func (tuc *tokenUseCase) RevokeCustomer(ctx context.Context, customerID string) error {
+	return tuc.repo.RevokeCustomer(ctx, customerID, time.Now())
+}
+
+// Check implements TokenUseCase. Tokens without a jti or issue time predate
+// revocation support and are refused.
+func (tuc *tokenUseCase) Check(ctx context.Context, claims utils.Claims) error {
+	if claims.RegisteredClaims.ID == "" || claims.IssuedAt == nil {
+		return domain.ErrTokenRevoked
+	}
+	revoked, err := tuc.repo.IsAccessTokenRevoked(ctx, claims.RegisteredClaims.ID, claims.ID, claims.IssuedAt.Time)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return domain.ErrTokenRevoked
+	}
+	return nil
+}
+
+// Purge implements TokenUseCase.
+func (tuc *tokenUseCase) Purge(ctx context.Context) (int64, error) {
+	return tuc.repo.DeleteExpired(ctx)
+}
+
+func (tuc *tokenUseCase) pair(customer domain.Customer, refresh string) (*presenter.TokenResponse, error) {
+	access, err := utils.GenerateJWT(customer.ID, customer.Name, customer.Email, string(customer.Role))
+	if err != nil {
+		return nil, err
+	}
+	return &presenter.TokenResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(utils.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+func NewTokenUseCase(repo tokenrepo.TokenRepository, customers repositories.CustomerRepository, refreshTTL time.Duration) TokenUseCase {
+	return &tokenUseCase{
+		logger:     utils.NewLogger("usecaseToken"),
+		repo:       repo,
+		customers:  customers,
+		refreshTTL: refreshTTL,
+	}
+}
@@ -0,0 +1,254 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/customer/internal/customer/repositories"
+	"github.com/adilsonmenechini/golabbank/customer/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/customer/internal/domain"
+	tokenrepo "github.com/adilsonmenechini/golabbank/customer/internal/token/repositories"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Exit(m.Run())
+}
+
+// fakeTokens keeps tokens in memory and applies the same redemption rule as
+// the SQL repository, under one lock in place of the row lock.
+type fakeTokens struct {
+	tokenrepo.TokenRepository
+	mu      sync.Mutex
+	refresh map[string]*domain.RefreshToken
+	denied  map[string]bool
+	cutoffs map[string]time.Time
+}
+
+func newFakeTokens() *fakeTokens {
+	return &fakeTokens{
+		refresh: make(map[string]*domain.RefreshToken),
+		denied:  make(map[string]bool),
+		cutoffs: make(map[string]time.Time),
+	}
+}
+
+func (f *fakeTokens) CreateRefreshToken(ctx context.Context, t *domain.RefreshToken) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.refresh[t.TokenHash] = t
+	return nil
+}
+
+func (f *fakeTokens) RotateRefreshToken(ctx context.Context, tokenHash string, next func(cur *domain.RefreshToken) (*domain.RefreshToken, error)) (*domain.RefreshToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cur, ok := f.refresh[tokenHash]
+	if !ok {
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+	now := time.Now().UTC()
+	if err := cur.Redeem(now); errors.Is(err, domain.ErrRefreshTokenReused) {
+		f.revoke(func(t *domain.RefreshToken) bool { return t.FamilyID == cur.FamilyID }, now)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+	cur.UsedAt = &now
+	nt, err := next(cur)
+	if err != nil {
+		return nil, err
+	}
+	f.refresh[nt.TokenHash] = nt
+	return nt, nil
+}
+
+func (f *fakeTokens) RevokeRefreshToken(ctx context.Context, tokenHash, customerID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if cur, ok := f.refresh[tokenHash]; ok && cur.CustomerID == customerID {
+		f.revoke(func(t *domain.RefreshToken) bool { return t.FamilyID == cur.FamilyID }, time.Now().UTC())
+	}
+	return nil
+}
+
+func (f *fakeTokens) RevokeCustomer(ctx context.Context, customerID string, notBefore time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.revoke(func(t *domain.RefreshToken) bool { return t.CustomerID == customerID }, time.Now().UTC())
+	if notBefore.After(f.cutoffs[customerID]) {
+		f.cutoffs[customerID] = notBefore
+	}
+	return nil
+}
+
+func (f *fakeTokens) DenyAccessToken(ctx context.Context, jti, customerID string, expiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.denied[jti] = true
+	return nil
+}
+
+func (f *fakeTokens) IsAccessTokenRevoked(ctx context.Context, jti, customerID string, issuedAt time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.denied[jti] || f.cutoffs[customerID].After(issuedAt), nil
+}
+
+func (f *fakeTokens) revoke(match func(t *domain.RefreshToken) bool, at time.Time) {
+	for _, t := range f.refresh {
+		if match(t) && t.RevokedAt == nil {
+			t.RevokedAt = &at
+		}
+	}
+}
+
+type fakeCustomers struct {
+	repositories.CustomerRepository
+}
+
+func (fakeCustomers) GetIDCustomer(ctx context.Context, id string) (domain.Customer, error) {
+	return domain.NewCustomer(id, "Alice", "alice@example.com", ""), nil
+}
+
+var alice = domain.NewCustomer("alice", "Alice", "alice@example.com", "")
+
+func newTestTokenUseCase() TokenUseCase {
+	return NewTokenUseCase(newFakeTokens(), fakeCustomers{}, time.Hour)
+}
+
+func TestRotatedRefreshTokenCannotBeReused(t *testing.T) {
+	uc := newTestTokenUseCase()
+	ctx := context.Background()
+
+	first, err := uc.Issue(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == "" {
+		t.Fatalf("refresh did not hand out a new pair: %+v", second)
+	}
+	if _, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: first.RefreshToken}); !errors.Is(err, domain.ErrRefreshTokenReused) {
+		t.Fatalf("reusing a rotated token: err = %v, want ErrRefreshTokenReused", err)
+	}
+}
+
+// TestReplayRevokesTheFamily replays a rotated token: the token it was
+// rotated into, held by whoever replayed or by the customer, is revoked too,
+// while another sign-in of the customer is left alone.
+func TestReplayRevokesTheFamily(t *testing.T) {
+	uc := newTestTokenUseCase()
+	ctx := context.Background()
+
+	stolen, err := uc.Issue(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherDevice, err := uc.Issue(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: stolen.RefreshToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: stolen.RefreshToken}); !errors.Is(err, domain.ErrRefreshTokenReused) {
+		t.Fatalf("replay: err = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: rotated.RefreshToken}); err == nil {
+		t.Fatal("the token the replayed one was rotated into still works")
+	}
+	if _, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: otherDevice.RefreshToken}); err != nil {
+		t.Fatalf("another sign-in of the customer: %v", err)
+	}
+}
+
+func TestRefreshRejectsUnknownAndExpiredTokens(t *testing.T) {
+	uc := NewTokenUseCase(newFakeTokens(), fakeCustomers{}, -time.Minute)
+	ctx := context.Background()
+
+	if _, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: "made-up"}); !errors.Is(err, domain.ErrRefreshTokenInvalid) {
+		t.Fatalf("unknown token: err = %v, want ErrRefreshTokenInvalid", err)
+	}
+	expired, err := uc.Issue(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: expired.RefreshToken}); !errors.Is(err, domain.ErrRefreshTokenInvalid) {
+		t.Fatalf("expired token: err = %v, want ErrRefreshTokenInvalid", err)
+	}
+}
+
+func accessClaims(jti string, issued time.Time) utils.Claims {
+	return utils.Claims{
+		ID: alice.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(issued),
+			ExpiresAt: jwt.NewNumericDate(issued.Add(time.Hour)),
+		},
+	}
+}
+
+func TestLogoutDenylistsTheAccessToken(t *testing.T) {
+	uc := newTestTokenUseCase()
+	session, err := uc.Issue(context.Background(), alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := accessClaims("jti-current", time.Now())
+	other := accessClaims("jti-other", time.Now())
+
+	ctx := utils.ContextWithClaims(context.Background(), current)
+	if err := uc.Logout(ctx, presenter.LogoutRequest{RefreshToken: session.RefreshToken}); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.Check(ctx, current); !errors.Is(err, domain.ErrTokenRevoked) {
+		t.Fatalf("denylisted token: err = %v, want ErrTokenRevoked", err)
+	}
+	if err := uc.Check(ctx, other); err != nil {
+		t.Fatalf("another access token of the customer: %v", err)
+	}
+	if _, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: session.RefreshToken}); err == nil {
+		t.Fatal("the refresh token given to Logout still works")
+	}
+}
+
+func TestRevokeCustomerCutsOffEarlierTokens(t *testing.T) {
+	uc := newTestTokenUseCase()
+	ctx := context.Background()
+	session, err := uc.Issue(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := accessClaims("jti-before", time.Now())
+
+	if err := uc.RevokeCustomer(ctx, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	after := accessClaims("jti-after", time.Now())
+
+	if err := uc.Check(ctx, before); !errors.Is(err, domain.ErrTokenRevoked) {
+		t.Fatalf("token issued before the cutoff: err = %v, want ErrTokenRevoked", err)
+	}
+	if err := uc.Check(ctx, after); err != nil {
+		t.Fatalf("token issued after the cutoff: %v", err)
+	}
+	if _, err := uc.Refresh(ctx, presenter.RefreshRequest{RefreshToken: session.RefreshToken}); err == nil {
+		t.Fatal("a refresh token issued before the cutoff still works")
+	}
+	if err := uc.Check(ctx, accessClaims("", time.Now())); !errors.Is(err, domain.ErrTokenRevoked) {
+		t.Fatalf("token without a jti: err = %v, want ErrTokenRevoked", err)
+	}
+}
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS "refresh_tokens" (
+  "id" VARCHAR(255) PRIMARY KEY,
+  "family_id" VARCHAR(255) NOT NULL,
+  "customer_id" VARCHAR(255) NOT NULL,
+  "token_hash" CHAR(64) NOT NULL UNIQUE,
+  "expires_at" TIMESTAMP NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "used_at" TIMESTAMP,
+  "revoked_at" TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "refresh_tokens_family_idx" ON "refresh_tokens" ("family_id");
+CREATE INDEX IF NOT EXISTS "refresh_tokens_customer_idx" ON "refresh_tokens" ("customer_id");
+
+-- Access tokens revoked before they expire, keyed by their jti.
+CREATE TABLE IF NOT EXISTS "revoked_tokens" (
+  "jti" VARCHAR(255) PRIMARY KEY,
+  "customer_id" VARCHAR(255) NOT NULL,
+  "expires_at" TIMESTAMP NOT NULL
+);
+
+-- Access tokens of a customer issued before not_before are revoked.
+CREATE TABLE IF NOT EXISTS "token_cutoffs" (
+  "customer_id" VARCHAR(255) PRIMARY KEY,
+  "not_before" TIMESTAMP NOT NULL
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "password_resets" (
+  "id" VARCHAR(255) PRIMARY KEY,
+  "customer_id" VARCHAR(255) NOT NULL,
+  "token_hash" CHAR(64) NOT NULL UNIQUE,
+  "expires_at" TIMESTAMP NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "used_at" TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "password_resets_customer_idx" ON "password_resets" ("customer_id");
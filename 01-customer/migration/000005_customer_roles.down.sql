@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "customers_role_idx";
+
+ALTER TABLE "customers" DROP COLUMN IF EXISTS "role";
@@ -0,0 +1,3 @@
+ALTER TABLE "customers" ADD COLUMN IF NOT EXISTS "role" VARCHAR(16) NOT NULL DEFAULT 'customer';
+
+CREATE INDEX IF NOT EXISTS "customers_role_idx" ON "customers" ("role");
@@ -1,69 +0,0 @@
-package utils
-
-import (
-	"errors"
-	"fmt"
-	"os"
-	"time"
-
-	"github.com/golang-jwt/jwt/v5"
-)
-
-var (
-	jwtSecret = []byte(os.Getenv("JWT_SECRET"))
-)
-
-type Claims struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
-	jwt.RegisteredClaims
-}
-
-func GenerateJWT(id, email string) (string, error) {
-
-	expirationTime := time.Now().Add(30 * time.Second)
-	claims := &Claims{
-		ID:    id,
-		Email: email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	// Create the JWT string
-	tokenString, err := token.SignedString(jwtSecret)
-	if err != nil {
-		return "", err
-	}
-
-	if err != nil {
-		fmt.Errorf("Something Went Wrong: %s", err.Error())
-		return "", err
-	}
-
-	return tokenString, nil
-}
-
-func ValidateToken(token string) (string, error) {
-
-	claims := &Claims{}
-
-	tkn, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
-		return jwtSecret, nil
-	})
-	if err != nil {
-		if err == jwt.ErrSignatureInvalid {
-			return "", err
-		}
-		return "", err
-	}
-	if !tkn.Valid {
-		return "", err
-	}
-
-	if claims.ExpiresAt.Before(time.Now()) {
-		return "", errors.New("Token Expired")
-	}
-
-	return claims.Email, nil
-}
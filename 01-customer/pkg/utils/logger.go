@@ -1,72 +0,0 @@
-package utils
-
-import (
-	"io"
-	"log"
-	"os"
-)
-
-type Logger struct {
-	debug   *log.Logger
-	info    *log.Logger
-	warning *log.Logger
-	err     *log.Logger
-	writer  io.Writer
-}
-
-type response struct {
-	statucCode int
-	message    interface{}
-}
-
-type Loggers interface {
-	Debug(v ...interface{})
-	Debugf(format string, v ...interface{})
-	Info(v ...interface{})
-	Infof(format string, v ...interface{})
-	Warn(v ...interface{})
-	Warnf(format string, v ...interface{})
-	Error(v ...interface{})
-	Errorf(format string, v ...interface{})
-}
-
-func NewLogger(p string) *Logger {
-	writer := io.Writer(os.Stdout)
-	logger := log.New(writer, p, log.Ldate|log.Ltime)
-
-	return &Logger{
-		debug:   log.New(writer, "DEBUG: ", logger.Flags()),
-		info:    log.New(writer, "INFO: ", logger.Flags()),
-		warning: log.New(writer, "WARNING: ", logger.Flags()),
-		err:     log.New(writer, "ERROR: ", logger.Flags()),
-		writer:  writer,
-	}
-}
-
-// Create Non-Formatted Logs
-func (l *Logger) Debug(v ...interface{}) {
-	l.debug.Println(v...)
-}
-func (l *Logger) Info(v ...interface{}) {
-	l.info.Println(v...)
-}
-func (l *Logger) Warn(v ...interface{}) {
-	l.warning.Println(v...)
-}
-func (l *Logger) Error(v ...interface{}) {
-	l.err.Println(v...)
-}
-
-// Create Format Enabled Logs
-func (l *Logger) Debugf(format string, v ...interface{}) {
-	l.debug.Printf(format, v...)
-}
-func (l *Logger) Infof(format string, v ...interface{}) {
-	l.info.Printf(format, v...)
-}
-func (l *Logger) Warnf(format string, v ...interface{}) {
-	l.warning.Printf(format, v...)
-}
-func (l *Logger) Errorf(format string, v ...interface{}) {
-	l.err.Printf(format, v...)
-}
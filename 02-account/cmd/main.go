@@ -1,15 +1,56 @@
 package main
 
 import (
-	"github.com/adilsonmenechini/golabbank/config"
-	"github.com/adilsonmenechini/golabbank/internal/delivery/router"
-	"github.com/adilsonmenechini/golabbank/pkg/database"
+	"context"
+	"log"
+	"os"
+
+	"github.com/adilsonmenechini/golabbank/account/config"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/router"
+	"github.com/adilsonmenechini/golabbank/platform/database"
+	"github.com/adilsonmenechini/golabbank/platform/metrics"
+	"github.com/adilsonmenechini/golabbank/platform/server"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
 )
 
 func main() {
 
 	config.ParseEnvVariables()
-	dbcon := database.ConnectPSQL()
-	router.Router(dbcon)
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+
+}
+
+// run serves until SIGINT or SIGTERM, then drains requests, stops the
+// background sweeps and closes the database and the span exporter.
+func run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shutdown, err := tracing.Setup(ctx, "account")
+	if err != nil {
+		return err
+	}
+	dbcon, err := database.ConnectPSQL(ctx)
+	if err != nil {
+		shutdown(ctx)
+		return err
+	}
+	metrics.RegisterDB(dbcon, os.Getenv("DB_NAME"))
+
+	h, err := router.Router(ctx, dbcon)
+	if err != nil {
+		dbcon.Close()
+		shutdown(ctx)
+		return err
+	}
 
+	srv := server.New(server.ConfigFromEnv(), h)
+	srv.OnShutdown(shutdown)
+	srv.OnShutdown(func(context.Context) error {
+		cancel()
+		return dbcon.Close()
+	})
+	return srv.Run(ctx)
 }
@@ -1,30 +1,39 @@
 package config
 
 import (
-	"log"
+	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
-	"github.com/joho/godotenv"
+	platformconfig "github.com/adilsonmenechini/golabbank/platform/config"
 )
 
-type EnvConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBname   string
-	Token    string
-}
-
 func ParseEnvVariables() {
 	_, b, _, _ := runtime.Caller(0)
 	basepath := filepath.Dir(b)
 
-	err := godotenv.Load(basepath + "/../deploy/.env")
+	platformconfig.Load(basepath + "/../deploy/.env")
+}
 
-	if err != nil {
-		log.Fatalf("Error loading .env files")
-	}
+// GetDuration reads a duration such as "24h" from the environment, falling
+// back to def when the variable is unset or malformed.
+func GetDuration(key string, def time.Duration) time.Duration {
+	return platformconfig.GetDuration(key, def)
+}
 
+// GetInt reads an integer from the environment, falling back to def when the
+// variable is unset or malformed.
+func GetInt(key string, def int) int {
+	return platformconfig.GetInt(key, def)
+}
+
+// ProductsFile is the account-product catalog to load: ACCOUNT_PRODUCTS_FILE
+// when set, otherwise the products.json shipped next to this package.
+func ProductsFile() string {
+	if v := os.Getenv("ACCOUNT_PRODUCTS_FILE"); v != "" {
+		return v
+	}
+	_, b, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(b), "products.json")
 }
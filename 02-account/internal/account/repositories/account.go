@@ -4,153 +4,141 @@ import (
 	"context"
 	"database/sql"
 
-	"github.com/adilsonmenechini/golabbank/internal/domain"
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
 )
 
 type (
 	AccountRepository interface {
-		CreateAccount(ctx context.Context, customerID, name, accType string, inLimit float64) error
-		DeleteAccount(ctx context.Context, accountNumber string) error
+		CreateAccount(ctx context.Context, acc *domain.Account) error
+		ChangeStatus(ctx context.Context, accountNumber string, apply func(acc *domain.Account) error) (*domain.Account, error)
+		ChangeLimit(ctx context.Context, accountNumber string, apply func(acc *domain.Account) error) (*domain.Account, error)
 		GetAccountNumber(ctx context.Context, accountNumber string) (*domain.Account, error)
 		GetCustomerID(ctx context.Context, customer string) (*domain.Account, error)
-		Deposit(ctx context.Context, amount float64, accountNumber string) error
-		Withdraw(ctx context.Context, amount float64, accountNumber string) error
-		Transfer(ctx context.Context, amount float64, fromAccountNumber string, toAccountNumber string) error
-		Payment(ctx context.Context, amount float64, accountNumber string) error
-		PaymentLimit(ctx context.Context, amount float64, accountNumber string) error
+		ListCustomerAccounts(ctx context.Context, customer string) ([]*domain.Account, error)
+		Deposit(ctx context.Context, amount domain.Money, accountNumber string) error
+		Withdraw(ctx context.Context, amount domain.Money, accountNumber string) error
+		Transfer(ctx context.Context, amount domain.Money, fromAccountNumber string, toAccountNumber string) error
+		Payment(ctx context.Context, amount domain.Money, accountNumber string) error
+		PaymentLimit(ctx context.Context, amount domain.Money, accountNumber string) error
+		Reverse(ctx context.Context, transactionID string) ([]domain.Transaction, error)
+		Refund(ctx context.Context, transactionID string, amount domain.Money) (*domain.Transaction, error)
 	}
 
 	accountRepository struct {
 		logger *utils.Logger
 		db     *sql.DB
+		ledger TransactionRepository
 	}
 )
 
 // GetCustomerID implements AccountRepository.
 func (acr *accountRepository) GetCustomerID(ctx context.Context, customer string) (*domain.Account, error) {
-	var i domain.Account
-
-	i.Mu.Lock()
-	defer i.Mu.Unlock()
-
 	row := acr.db.QueryRowContext(ctx, getCustomerID, customer)
 
-	err := row.Scan(
-		&i.AccountNumber,
-		&i.AccountType,
-		&i.CustomerID,
-		&i.Name,
-		&i.Balance,
-		&i.Limit,
-		&i.Reversal,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
+	i, err := scanAccount(row)
 	if err != nil {
 		return &domain.Account{}, err
 	}
 
-	return &i, nil
+	return i, nil
 }
 
-// Transfer implements AccountRepository.
-func (acr *accountRepository) Transfer(ctx context.Context, amount float64, fromAccountNumber string, toAccountNumber string) error {
-	fromacc, err := acr.GetAccountNumber(ctx, fromAccountNumber)
+// ListCustomerAccounts implements AccountRepository.
+func (acr *accountRepository) ListCustomerAccounts(ctx context.Context, customer string) ([]*domain.Account, error) {
+	rows, err := acr.db.QueryContext(ctx, listCustomerAccounts, customer)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	toacc, err := acr.GetAccountNumber(ctx, toAccountNumber)
-	if err != nil {
-		return err
+	defer rows.Close()
+
+	var items []*domain.Account
+	for rows.Next() {
+		i, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
 	}
-	toAcc, err := fromacc.Transfer(toacc, amount)
-	if err != nil {
-		return err
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
+	return items, nil
+}
 
-	_, err = acr.db.ExecContext(ctx, updatePayment,
-		fromacc.AccountNumber,
-		fromacc.Balance,
-		fromacc.Limit,
-		fromacc.UpdatedAt,
-	)
-	if err != nil {
-		return err
+// Transfer implements AccountRepository.
+//
+// Both rows are locked inside a single database transaction. Locks are always
+// taken in account number order so two opposite transfers between the same
+// pair of accounts cannot deadlock each other.
+func (acr *accountRepository) Transfer(ctx context.Context, amount domain.Money, fromAccountNumber string, toAccountNumber string) error {
+	if fromAccountNumber == toAccountNumber {
+		return domain.ErrTransferSameAccount
 	}
 
-	_, err = acr.db.ExecContext(ctx, updatePayment,
-		toAcc.AccountNumber,
-		toAcc.Balance,
-		toAcc.Limit,
-		toAcc.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-	return nil
+	return InTx(ctx, acr.db, func(tx *sql.Tx) error {
+		first, second := fromAccountNumber, toAccountNumber
+		if second < first {
+			first, second = second, first
+		}
+		locked := make(map[string]*domain.Account, 2)
+		for _, number := range []string{first, second} {
+			acc, err := LockAccount(ctx, tx, number)
+			if err != nil {
+				return err
+			}
+			locked[number] = acc
+		}
+
+		fromacc, toacc := locked[fromAccountNumber], locked[toAccountNumber]
+		fromBefore, toBefore := fromacc.Balance, toacc.Balance
+		toAcc, err := fromacc.Transfer(toacc, amount)
+		if err != nil {
+			return err
+		}
+
+		if err := UpdateAccount(ctx, tx, fromacc); err != nil {
+			return err
+		}
+		if err := UpdateAccount(ctx, tx, toAcc); err != nil {
+			return err
+		}
+
+		ledger := acr.ledger.WithTx(tx)
+		operationID := utils.GenerateUUID()
+		if err := acr.record(ctx, ledger, domain.NewTransaction(operationID, fromacc.AccountNumber, domain.Transfer, amount, fromBefore, fromacc.Balance, toAcc.AccountNumber)); err != nil {
+			return err
+		}
+		return acr.record(ctx, ledger, domain.NewTransaction(operationID, toAcc.AccountNumber, domain.Transfer, amount, toBefore, toAcc.Balance, fromacc.AccountNumber))
+	})
 }
 
 // Deposit implements AccountRepository.
-func (acr *accountRepository) Deposit(ctx context.Context, amount float64, accountNumber string) error {
-	acc, err := acr.GetAccountNumber(ctx, accountNumber)
-	if err != nil {
-		return err
-	}
-	if err := acc.Deposit(amount); err != nil {
-		return err
-	}
-
-	_, err = acr.db.ExecContext(ctx, updatePayment,
-		acc.AccountNumber,
-		acc.Balance,
-		acc.Limit,
-		acc.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-	return nil
+func (acr *accountRepository) Deposit(ctx context.Context, amount domain.Money, accountNumber string) error {
+	return acr.mutate(ctx, accountNumber, domain.Deposit, amount, func(acc *domain.Account) error {
+		return acc.Deposit(amount)
+	})
 }
 
 // Withdraw implements AccountRepository.
-func (acr *accountRepository) Withdraw(ctx context.Context, amount float64, accountNumber string) error {
-	acc, err := acr.GetAccountNumber(ctx, accountNumber)
-	if err != nil {
-		return err
-	}
-	if err := acc.Withdraw(amount); err != nil {
-		return err
-	}
-
-	_, err = acr.db.ExecContext(ctx, updatePayment,
-		acc.AccountNumber,
-		acc.Balance,
-		acc.Limit,
-		acc.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-	return nil
+func (acr *accountRepository) Withdraw(ctx context.Context, amount domain.Money, accountNumber string) error {
+	return acr.mutate(ctx, accountNumber, domain.Withdraw, amount, func(acc *domain.Account) error {
+		return acc.Withdraw(amount)
+	})
 }
 
 // CreateAccount implements AccountRepository.
-func (acr *accountRepository) CreateAccount(ctx context.Context, customerID, name, accType string, inLimit float64) error {
-	input := domain.Customer{
-		ID:   customerID,
-		Name: name,
-	}
-	newAcc := domain.NewAccount(&input, accType, inLimit)
-
+func (acr *accountRepository) CreateAccount(ctx context.Context, newAcc *domain.Account) error {
 	_, err := acr.db.ExecContext(ctx, createAccount,
 		newAcc.AccountNumber,
 		newAcc.AccountType,
 		newAcc.CustomerID,
 		newAcc.Name,
-		newAcc.Balance,
-		newAcc.Limit,
-		newAcc.Reversal,
+		newAcc.Balance.MinorUnits(),
+		newAcc.Limit.MinorUnits(),
+		newAcc.Reversal.MinorUnits(),
+		newAcc.Currency(),
+		newAcc.Status,
 		newAcc.CreatedAt,
 		newAcc.UpdatedAt,
 	)
@@ -160,95 +148,173 @@ func (acr *accountRepository) CreateAccount(ctx context.Context, customerID, nam
 	return nil
 }
 
-// DeleteAccount implements AccountRepository.
-func (acr *accountRepository) DeleteAccount(ctx context.Context, accountNumber string) error {
-	_, err := acr.db.ExecContext(ctx, deleteAccount, accountNumber)
+// ChangeStatus implements AccountRepository. apply runs on the locked row and
+// is expected to call one of the Account lifecycle methods.
+func (acr *accountRepository) ChangeStatus(ctx context.Context, accountNumber string, apply func(acc *domain.Account) error) (*domain.Account, error) {
+	var acc *domain.Account
+
+	err := InTx(ctx, acr.db, func(tx *sql.Tx) error {
+		var err error
+		acc, err = LockAccount(ctx, tx, accountNumber)
+		if err != nil {
+			return err
+		}
+		if err := apply(acc); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, updateStatus, acc.AccountNumber, acc.Status, acc.UpdatedAt)
+		return err
+	})
 	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// ChangeLimit implements AccountRepository. apply runs on the locked row and
+// is expected to call Account.SetCreditLimit.
+func (acr *accountRepository) ChangeLimit(ctx context.Context, accountNumber string, apply func(acc *domain.Account) error) (*domain.Account, error) {
+	var acc *domain.Account
+
+	err := InTx(ctx, acr.db, func(tx *sql.Tx) error {
+		var err error
+		acc, err = LockAccount(ctx, tx, accountNumber)
+		if err != nil {
+			return err
+		}
+		if err := apply(acc); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, updateLimit, acc.AccountNumber, acc.Limit.MinorUnits(), acc.Reversal.MinorUnits(), acc.UpdatedAt)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return acc, nil
 }
 
 // GetAccountNumber implements AccountRepository.
 func (acr *accountRepository) GetAccountNumber(ctx context.Context, accountNumber string) (*domain.Account, error) {
-	var i domain.Account
-
-	i.Mu.Lock()
-	defer i.Mu.Unlock()
-
 	row := acr.db.QueryRowContext(ctx, getAccountNumber, accountNumber)
 
-	err := row.Scan(
-		&i.AccountNumber,
-		&i.AccountType,
-		&i.CustomerID,
-		&i.Name,
-		&i.Balance,
-		&i.Limit,
-		&i.Reversal,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
+	i, err := scanAccount(row)
 	if err != nil {
 		return &domain.Account{}, err
 	}
 
-	return &i, nil
+	return i, nil
 }
 
-// UpdateAccount implements AccountRepository.
-func (acr *accountRepository) Payment(ctx context.Context, amount float64, accountNumber string) error {
-	account, err := acr.GetAccountNumber(ctx, accountNumber)
-	if err != nil {
-		return err
-	}
-	if err := account.Payment(amount); err != nil {
-		return err
-	}
+// Payment implements AccountRepository.
+func (acr *accountRepository) Payment(ctx context.Context, amount domain.Money, accountNumber string) error {
+	return acr.mutate(ctx, accountNumber, domain.Payment, amount, func(acc *domain.Account) error {
+		return acc.Payment(amount)
+	})
+}
 
-	_, err = acr.db.ExecContext(ctx, updatePayment,
-		account.AccountNumber,
-		account.Balance,
-		account.Limit,
-		account.UpdatedAt,
-	)
-	if err != nil {
+// PaymentLimit implements AccountRepository.
+func (acr *accountRepository) PaymentLimit(ctx context.Context, amount domain.Money, accountNumber string) error {
+	return acr.mutate(ctx, accountNumber, domain.PaymentLimit, amount, func(acc *domain.Account) error {
+		return acc.PaymentLimit(amount)
+	})
+}
+
+// mutate applies a single-account balance change as one read-modify-write:
+// the row is locked, changed, written back and recorded in the ledger inside
+// the same database transaction. Whatever the change drew on the credit line
+// is recorded with the entry, so a reversal can give it back to the line.
+func (acr *accountRepository) mutate(ctx context.Context, accountNumber string, tType domain.TransactionType, amount domain.Money, apply func(acc *domain.Account) error) error {
+	return InTx(ctx, acr.db, func(tx *sql.Tx) error {
+		acc, err := LockAccount(ctx, tx, accountNumber)
+		if err != nil {
+			return err
+		}
+		before, limitBefore := acc.Balance, acc.Limit
+		if err := apply(acc); err != nil {
+			return err
+		}
+		if err := UpdateAccount(ctx, tx, acc); err != nil {
+			return err
+		}
+		entry := domain.NewTransaction(utils.GenerateUUID(), acc.AccountNumber, tType, amount, before, acc.Balance, "")
+		if drawn, err := limitBefore.Sub(acc.Limit); err == nil && drawn.IsPositive() {
+			entry.CreditAmount = drawn
+		}
+		return acr.record(ctx, acr.ledger.WithTx(tx), entry)
+	})
+}
+
+// record appends the ledger entry describing a mutation already applied to
+// its account.
+func (acr *accountRepository) record(ctx context.Context, ledger TransactionRepository, tx domain.Transaction) error {
+	if err := ledger.CreateTransaction(ctx, tx); err != nil {
+		acr.logger.WithContext(ctx).Errorf("error recording %s transaction for account %s: %v", tx.Type, tx.AccountNumber, err)
 		return err
 	}
 	return nil
 }
-func (acr *accountRepository) PaymentLimit(ctx context.Context, amount float64, accountNumber string) error {
-	account, err := acr.GetAccountNumber(ctx, accountNumber)
-	if err != nil {
-		return err
-	}
-	if err := account.PaymentLimit(amount); err != nil {
-		return err
-	}
 
-	_, err = acr.db.ExecContext(ctx, updatePayment,
-		account.AccountNumber,
-		account.Balance,
-		account.Limit,
-		account.UpdatedAt,
+// LockAccount reads an account row with FOR UPDATE. db should be a *sql.Tx.
+func LockAccount(ctx context.Context, db DBTX, accountNumber string) (*domain.Account, error) {
+	return scanAccount(db.QueryRowContext(ctx, lockAccountNumber, accountNumber))
+}
+
+// UpdateAccount writes back the balance and credit line of acc.
+func UpdateAccount(ctx context.Context, db DBTX, acc *domain.Account) error {
+	_, err := db.ExecContext(ctx, updatePayment,
+		acc.AccountNumber,
+		acc.Balance.MinorUnits(),
+		acc.Limit.MinorUnits(),
+		acc.UpdatedAt,
+	)
+	return err
+}
+
+func scanAccount(row rowScanner) (*domain.Account, error) {
+	var (
+		i                        domain.Account
+		balance, limit, reversal int64
+		currency                 string
+	)
+	err := row.Scan(
+		&i.AccountNumber,
+		&i.AccountType,
+		&i.CustomerID,
+		&i.Name,
+		&balance,
+		&limit,
+		&reversal,
+		&currency,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	i.Balance = domain.NewMoney(balance, currency)
+	i.Limit = domain.NewMoney(limit, currency)
+	i.Reversal = domain.NewMoney(reversal, currency)
+	return &i, nil
 }
+
 func NewAccountRepository(DB *sql.DB) AccountRepository {
 	return &accountRepository{
 		logger: utils.NewLogger("AccountRepository"),
 		db:     DB,
+		ledger: NewTransactionRepository(DB),
 	}
 }
 
 const (
-	createAccount    = `INSERT INTO Accounts (account_number, account_type, customer_id, name, balance, acc_limit, acc_reversal, created_at, updated_at) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9)`
-	deleteAccount    = `DELETE FROM Accounts WHERE accountNumber = $1`
-	getAccountNumber = `SELECT * FROM Accounts WHERE account_number = $1`
-	getCustomerID    = `SELECT * FROM Accounts WHERE customer_id = $1`
-	updatePayment    = `UPDATE Accounts set balance = $2,acc_limit = $3 ,updated_at = $4 WHERE account_number = $1`
-	depositWithdraw  = `UPDATE Accounts set balance = $2, updated_at = $3 WHERE account_number = $1`
+	accountColumns       = `account_number, account_type, customer_id, name, balance, acc_limit, acc_reversal, currency, status, created_at, updated_at`
+	createAccount        = `INSERT INTO Accounts (` + accountColumns + `) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	getAccountNumber     = `SELECT ` + accountColumns + ` FROM Accounts WHERE account_number = $1`
+	lockAccountNumber    = `SELECT ` + accountColumns + ` FROM Accounts WHERE account_number = $1 FOR UPDATE`
+	getCustomerID        = `SELECT ` + accountColumns + ` FROM Accounts WHERE customer_id = $1`
+	listCustomerAccounts = `SELECT ` + accountColumns + ` FROM Accounts WHERE customer_id = $1 ORDER BY created_at`
+	updatePayment        = `UPDATE Accounts set balance = $2,acc_limit = $3 ,updated_at = $4 WHERE account_number = $1`
+	updateStatus         = `UPDATE Accounts set status = $2, updated_at = $3 WHERE account_number = $1`
+	updateLimit          = `UPDATE Accounts set acc_limit = $2, acc_reversal = $3, updated_at = $4 WHERE account_number = $1`
 )
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	_ "github.com/lib/pq"
+)
+
+// openTestDB connects to the PostgreSQL named by TEST_DATABASE_URL, a
+// postgres:// URL, and applies the migrations to a schema of the test's own,
+// dropped when the test ends. The test is skipped when the variable is unset.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	files, err := filepath.Glob("../../../migration/*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		stmts, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(string(stmts)); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(f), err)
+		}
+	}
+	return db
+}
+
+func createTestAccount(t *testing.T, repo AccountRepository, balance int64) string {
+	t.Helper()
+	now := time.Now().UTC()
+	acc := &domain.Account{
+		AccountNumber: utils.GenerateUUID()[:8],
+		AccountType:   "checking",
+		CustomerID:    utils.GenerateUUID(),
+		Name:          "Test",
+		Balance:       domain.NewMoney(balance, "BRL"),
+		Limit:         domain.NewMoney(0, "BRL"),
+		Reversal:      domain.NewMoney(0, "BRL"),
+		Status:        domain.AccountActive,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := repo.CreateAccount(context.Background(), acc); err != nil {
+		t.Fatal(err)
+	}
+	return acc.AccountNumber
+}
+
+// TestConcurrentWithdrawals hammers one account with more withdrawals than
+// its balance covers. Row locking must let exactly as many through as the
+// balance pays for, each with its own ledger entry, and never overdraw.
+func TestConcurrentWithdrawals(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewAccountRepository(db)
+	ledger := NewTransactionRepository(db)
+	ctx := context.Background()
+
+	const (
+		workers = 50
+		amount  = 1000
+		covered = 20
+	)
+	number := createTestAccount(t, repo, amount*covered)
+
+	var (
+		wg                 sync.WaitGroup
+		mu                 sync.Mutex
+		succeeded, refused int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.Withdraw(ctx, domain.NewMoney(amount, "BRL"), number)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, domain.ErrWithdrawalInsufficient):
+				refused++
+			default:
+				t.Errorf("Withdraw: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != covered || refused != workers-covered {
+		t.Fatalf("succeeded %d, refused %d; want %d and %d", succeeded, refused, covered, workers-covered)
+	}
+	acc, err := repo.GetAccountNumber(ctx, number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acc.Balance.IsZero() {
+		t.Fatalf("balance = %s, want 0", acc.Balance)
+	}
+	txs, err := ledger.GetAccountTransactions(ctx, number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != covered {
+		t.Fatalf("%d ledger entries, want %d", len(txs), covered)
+	}
+}
+
+// TestConcurrentDepositsAndWithdrawals interleaves as many deposits as
+// withdrawals of the same amount: no update may be lost, so the balance ends
+// where it started.
+func TestConcurrentDepositsAndWithdrawals(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewAccountRepository(db)
+	ctx := context.Background()
+
+	const (
+		workers = 40
+		start   = 100000
+		amount  = 250
+	)
+	number := createTestAccount(t, repo, start)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := repo.Deposit(ctx, domain.NewMoney(amount, "BRL"), number); err != nil {
+				t.Errorf("Deposit: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := repo.Withdraw(ctx, domain.NewMoney(amount, "BRL"), number); err != nil {
+				t.Errorf("Withdraw: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	acc, err := repo.GetAccountNumber(ctx, number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Balance.MinorUnits() != start {
+		t.Fatalf("balance = %s, want %s", acc.Balance, domain.NewMoney(start, "BRL"))
+	}
+}
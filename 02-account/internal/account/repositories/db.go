@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX is satisfied by both *sql.DB and *sql.Tx, so repositories can run the
+// same queries inside or outside a database transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// InTx runs fn inside a database transaction, committing when fn succeeds
+// and rolling back otherwise.
+func InTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
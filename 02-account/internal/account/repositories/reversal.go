@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// Reverse implements AccountRepository. It posts one compensating Reversal
+// entry per leg of the original operation, so reversing a transfer restores
+// both accounts atomically.
+func (acr *accountRepository) Reverse(ctx context.Context, transactionID string) ([]domain.Transaction, error) {
+	var posted []domain.Transaction
+
+	err := InTx(ctx, acr.db, func(tx *sql.Tx) error {
+		ledger := acr.ledger.WithTx(tx)
+
+		legs, err := ledger.LockOperation(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+		if !legs[0].Reversible() {
+			return domain.ErrTransactionNotReversible
+		}
+
+		ids := make([]string, len(legs))
+		for i, leg := range legs {
+			ids[i] = leg.ID
+		}
+		if n, err := ledger.CountReferences(ctx, ids, domain.Reversal); err != nil {
+			return err
+		} else if n > 0 {
+			return domain.ErrTransactionReversed
+		}
+		if n, err := ledger.CountReferences(ctx, ids, domain.Refund); err != nil {
+			return err
+		} else if n > 0 {
+			return domain.ErrTransactionRefunded
+		}
+
+		accounts, err := lockAccounts(ctx, tx, legs)
+		if err != nil {
+			return err
+		}
+
+		operationID := utils.GenerateUUID()
+		for _, leg := range legs {
+			acc := accounts[leg.AccountNumber]
+			before := acc.Balance
+
+			switch {
+			case leg.Type == domain.Payment:
+				err = acc.ReversePayment(leg.Amount, leg.CreditAmount)
+			case leg.IsDebit():
+				err = acc.Credit(leg.Amount)
+			default:
+				err = acc.Debit(leg.Amount)
+			}
+			if err != nil {
+				return err
+			}
+			if err := UpdateAccount(ctx, tx, acc); err != nil {
+				return err
+			}
+
+			entry := domain.NewTransaction(operationID, acc.AccountNumber, domain.Reversal, leg.Amount, before, acc.Balance, leg.Counterparty)
+			entry.ReferenceID = leg.ID
+			if err := ledger.CreateTransaction(ctx, entry); err != nil {
+				return err
+			}
+			posted = append(posted, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return posted, nil
+}
+
+// Refund implements AccountRepository. Payments may be refunded in several
+// parts as long as the total never exceeds the original amount.
+func (acr *accountRepository) Refund(ctx context.Context, transactionID string, amount domain.Money) (*domain.Transaction, error) {
+	var posted domain.Transaction
+
+	err := InTx(ctx, acr.db, func(tx *sql.Tx) error {
+		ledger := acr.ledger.WithTx(tx)
+
+		legs, err := ledger.LockOperation(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+		orig := legs[0]
+		if orig.Type != domain.Payment {
+			return domain.ErrTransactionNotRefundable
+		}
+		if n, err := ledger.CountReferences(ctx, []string{orig.ID}, domain.Reversal); err != nil {
+			return err
+		} else if n > 0 {
+			return domain.ErrTransactionReversed
+		}
+		if !amount.SameCurrency(orig.Amount) {
+			return domain.ErrCurrencyMismatch
+		}
+
+		refunded, err := ledger.SumReferences(ctx, orig.ID, domain.Refund)
+		if err != nil {
+			return err
+		}
+		if amount.MinorUnits() > orig.Amount.MinorUnits()-refunded {
+			return domain.ErrRefundInsufficient
+		}
+
+		acc, err := LockAccount(ctx, tx, orig.AccountNumber)
+		if err != nil {
+			return err
+		}
+		before := acc.Balance
+		if err := acc.Refund(amount); err != nil {
+			return err
+		}
+		if err := UpdateAccount(ctx, tx, acc); err != nil {
+			return err
+		}
+
+		posted = domain.NewTransaction(utils.GenerateUUID(), acc.AccountNumber, domain.Refund, amount, before, acc.Balance, orig.Counterparty)
+		posted.ReferenceID = orig.ID
+		return ledger.CreateTransaction(ctx, posted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &posted, nil
+}
+
+// lockAccounts locks the accounts touched by legs in account number order.
+func lockAccounts(ctx context.Context, db DBTX, legs []domain.Transaction) (map[string]*domain.Account, error) {
+	numbers := make([]string, 0, len(legs))
+	seen := make(map[string]bool, len(legs))
+	for _, leg := range legs {
+		if !seen[leg.AccountNumber] {
+			seen[leg.AccountNumber] = true
+			numbers = append(numbers, leg.AccountNumber)
+		}
+	}
+	sort.Strings(numbers)
+
+	locked := make(map[string]*domain.Account, len(numbers))
+	for _, number := range numbers {
+		acc, err := LockAccount(ctx, db, number)
+		if err != nil {
+			return nil, err
+		}
+		locked[number] = acc
+	}
+	return locked, nil
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+)
+
+// TestReversePaymentRestoresChargedSource pays from the balance, then lets a
+// second payment draw on the credit line. Reversing the first must credit the
+// balance back and leave the credit in use by the second untouched.
+func TestReversePaymentRestoresChargedSource(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewAccountRepository(db)
+	ledger := NewTransactionRepository(db)
+	ctx := context.Background()
+
+	number := createTestAccount(t, repo, 20000)
+	if _, err := repo.ChangeLimit(ctx, number, func(acc *domain.Account) error {
+		return acc.SetCreditLimit(domain.NewMoney(10000, "BRL"), domain.NewMoney(10000, "BRL"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, amount := range []int64{10000, 15000} {
+		if err := repo.Payment(ctx, domain.NewMoney(amount, "BRL"), number); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txs, err := ledger.GetAccountTransactions(ctx, number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 || txs[0].CreditAmount.MinorUnits() != 0 || txs[1].CreditAmount.MinorUnits() != 5000 {
+		t.Fatalf("ledger does not record the credit drawn: %+v", txs)
+	}
+
+	if _, err := repo.Reverse(ctx, txs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	acc, err := repo.GetAccountNumber(ctx, number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Balance.MinorUnits() != 10000 || acc.Limit.MinorUnits() != 5000 {
+		t.Fatalf("balance %s, limit %s; want 100.00 and 50.00", acc.Balance, acc.Limit)
+	}
+}
@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/lib/pq"
+)
+
+type (
+	TransactionRepository interface {
+		CreateTransaction(ctx context.Context, tx domain.Transaction) error
+		GetTransactionID(ctx context.Context, id string) (*domain.Transaction, error)
+		GetAccountTransactions(ctx context.Context, accountNumber string) ([]domain.Transaction, error)
+		ListTransactions(ctx context.Context, filter domain.StatementFilter) ([]domain.Transaction, error)
+		StreamTransactions(ctx context.Context, filter domain.StatementFilter, fn func(tx domain.Transaction) error) error
+		OpeningBalance(ctx context.Context, accountNumber string, from time.Time) (domain.Money, bool, error)
+		LockOperation(ctx context.Context, id string) ([]domain.Transaction, error)
+		CountReferences(ctx context.Context, referenceIDs []string, tType domain.TransactionType) (int, error)
+		SumReferences(ctx context.Context, referenceID string, tType domain.TransactionType) (int64, error)
+		WithTx(tx *sql.Tx) TransactionRepository
+	}
+
+	transactionRepository struct {
+		logger *utils.Logger
+		db     DBTX
+	}
+)
+
+// CreateTransaction implements TransactionRepository.
+func (tr *transactionRepository) CreateTransaction(ctx context.Context, tx domain.Transaction) error {
+	_, err := tr.db.ExecContext(ctx, createTransaction,
+		tx.ID,
+		tx.OperationID,
+		sql.NullString{String: tx.ReferenceID, Valid: tx.ReferenceID != ""},
+		tx.AccountNumber,
+		tx.Type,
+		tx.Amount.MinorUnits(),
+		tx.BalanceBefore.MinorUnits(),
+		tx.BalanceAfter.MinorUnits(),
+		tx.CreditAmount.MinorUnits(),
+		tx.Amount.Currency(),
+		tx.Counterparty,
+		tx.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetTransactionID implements TransactionRepository.
+func (tr *transactionRepository) GetTransactionID(ctx context.Context, id string) (*domain.Transaction, error) {
+	row := tr.db.QueryRowContext(ctx, getTransactionID, id)
+
+	i, err := scanTransaction(row)
+	if err == sql.ErrNoRows {
+		return &domain.Transaction{}, domain.ErrTransactionNotFound
+	}
+	if err != nil {
+		return &domain.Transaction{}, err
+	}
+	return &i, nil
+}
+
+// GetAccountTransactions implements TransactionRepository.
+func (tr *transactionRepository) GetAccountTransactions(ctx context.Context, accountNumber string) ([]domain.Transaction, error) {
+	rows, err := tr.db.QueryContext(ctx, getAccountTransactions, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.Transaction
+	for rows.Next() {
+		i, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListTransactions implements TransactionRepository.
+func (tr *transactionRepository) ListTransactions(ctx context.Context, filter domain.StatementFilter) ([]domain.Transaction, error) {
+	var items []domain.Transaction
+	err := tr.StreamTransactions(ctx, filter, func(tx domain.Transaction) error {
+		items = append(items, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// StreamTransactions implements TransactionRepository. Rows are handed to fn
+// one at a time as they are read, so arbitrarily long histories can be
+// processed in constant memory. A zero filter.Limit means no limit.
+func (tr *transactionRepository) StreamTransactions(ctx context.Context, filter domain.StatementFilter, fn func(tx domain.Transaction) error) error {
+	var from, to, afterAt sql.NullTime
+	var limit sql.NullInt64
+	var afterID string
+	if !filter.From.IsZero() {
+		from = sql.NullTime{Time: filter.From, Valid: true}
+	}
+	if !filter.To.IsZero() {
+		to = sql.NullTime{Time: filter.To, Valid: true}
+	}
+	if filter.After != nil {
+		afterAt = sql.NullTime{Time: filter.After.CreatedAt, Valid: true}
+		afterID = filter.After.ID
+	}
+	if filter.Limit > 0 {
+		limit = sql.NullInt64{Int64: int64(filter.Limit), Valid: true}
+	}
+	types := make([]string, len(filter.Types))
+	for i, t := range filter.Types {
+		types[i] = string(t)
+	}
+
+	rows, err := tr.db.QueryContext(ctx, listTransactions,
+		filter.AccountNumber,
+		from,
+		to,
+		pq.Array(types),
+		afterAt,
+		afterID,
+		limit,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanTransaction(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// OpeningBalance implements TransactionRepository. It returns the balance the
+// account held at the instant from, and false when the ledger has no entry to
+// derive it from.
+func (tr *transactionRepository) OpeningBalance(ctx context.Context, accountNumber string, from time.Time) (domain.Money, bool, error) {
+	var (
+		amount   int64
+		currency string
+	)
+	err := tr.db.QueryRowContext(ctx, balanceBeforeFirst, accountNumber, from).Scan(&amount, &currency)
+	if err == sql.ErrNoRows {
+		err = tr.db.QueryRowContext(ctx, balanceAfterLast, accountNumber).Scan(&amount, &currency)
+	}
+	if err == sql.ErrNoRows {
+		return domain.Money{}, false, nil
+	}
+	if err != nil {
+		return domain.Money{}, false, err
+	}
+	return domain.NewMoney(amount, currency), true, nil
+}
+
+// LockOperation implements TransactionRepository. It locks every leg posted
+// by the same operation as entry id, always in account number order, and
+// returns them with id first. Only meaningful inside a database transaction.
+func (tr *transactionRepository) LockOperation(ctx context.Context, id string) ([]domain.Transaction, error) {
+	rows, err := tr.db.QueryContext(ctx, lockOperationLegs, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var legs []domain.Transaction
+	for rows.Next() {
+		i, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		if i.ID == id {
+			legs = append([]domain.Transaction{i}, legs...)
+		} else {
+			legs = append(legs, i)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(legs) == 0 {
+		return nil, domain.ErrTransactionNotFound
+	}
+	return legs, nil
+}
+
+// CountReferences implements TransactionRepository.
+func (tr *transactionRepository) CountReferences(ctx context.Context, referenceIDs []string, tType domain.TransactionType) (int, error) {
+	var n int
+	err := tr.db.QueryRowContext(ctx, countReferences, pq.Array(referenceIDs), tType).Scan(&n)
+	return n, err
+}
+
+// SumReferences implements TransactionRepository. The result is in the minor
+// units of the referenced entry's currency.
+func (tr *transactionRepository) SumReferences(ctx context.Context, referenceID string, tType domain.TransactionType) (int64, error) {
+	var n int64
+	err := tr.db.QueryRowContext(ctx, sumReferences, referenceID, tType).Scan(&n)
+	return n, err
+}
+
+// WithTx implements TransactionRepository.
+func (tr *transactionRepository) WithTx(tx *sql.Tx) TransactionRepository {
+	return &transactionRepository{
+		logger: tr.logger,
+		db:     tx,
+	}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTransaction(row rowScanner) (domain.Transaction, error) {
+	var (
+		i                     domain.Transaction
+		amount, before, after int64
+		credit                int64
+		currency              string
+		referenceID           sql.NullString
+	)
+	err := row.Scan(
+		&i.ID,
+		&i.OperationID,
+		&referenceID,
+		&i.AccountNumber,
+		&i.Type,
+		&amount,
+		&before,
+		&after,
+		&credit,
+		&currency,
+		&i.Counterparty,
+		&i.CreatedAt,
+	)
+	if err != nil {
+		return i, err
+	}
+	i.ReferenceID = referenceID.String
+	i.Amount = domain.NewMoney(amount, currency)
+	i.BalanceBefore = domain.NewMoney(before, currency)
+	i.BalanceAfter = domain.NewMoney(after, currency)
+	i.CreditAmount = domain.NewMoney(credit, currency)
+	return i, nil
+}
+
+func NewTransactionRepository(DB *sql.DB) TransactionRepository {
+	return &transactionRepository{
+		logger: utils.NewLogger("TransactionRepository"),
+		db:     DB,
+	}
+}
+
+const (
+	transactionColumns     = `id, operation_id, reference_id, account_number, type, amount, balance_before, balance_after, credit_amount, currency, counterparty, created_at`
+	createTransaction      = `INSERT INTO transactions (` + transactionColumns + `) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	getTransactionID       = `SELECT ` + transactionColumns + ` FROM transactions WHERE id = $1`
+	getAccountTransactions = `SELECT ` + transactionColumns + ` FROM transactions WHERE account_number = $1 ORDER BY created_at, id`
+	listTransactions       = `SELECT ` + transactionColumns + ` FROM transactions WHERE account_number = $1
+		AND ($2::timestamp IS NULL OR created_at >= $2)
+		AND ($3::timestamp IS NULL OR created_at < $3)
+		AND (cardinality($4::varchar[]) = 0 OR type = ANY($4))
+		AND ($5::timestamp IS NULL OR (created_at, id) > ($5, $6))
+		ORDER BY created_at, id LIMIT $7`
+	lockOperationLegs  = `SELECT ` + transactionColumns + ` FROM transactions WHERE (operation_id, type) = (SELECT operation_id, type FROM transactions WHERE id = $1) ORDER BY account_number FOR UPDATE`
+	countReferences    = `SELECT count(*) FROM transactions WHERE reference_id = ANY($1) AND type = $2`
+	sumReferences      = `SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE reference_id = $1 AND type = $2`
+	balanceBeforeFirst = `SELECT balance_before, currency FROM transactions WHERE account_number = $1 AND created_at >= $2 ORDER BY created_at, id LIMIT 1`
+	balanceAfterLast   = `SELECT balance_after, currency FROM transactions WHERE account_number = $1 ORDER BY created_at DESC, id DESC LIMIT 1`
+)
@@ -2,10 +2,18 @@ package usecases
 
 import (
 	"context"
-
-	"github.com/adilsonmenechini/golabbank/internal/account/repositories"
-	"github.com/adilsonmenechini/golabbank/internal/delivery/presenter"
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/account/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/customer/directory"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/account/pkg/genrand"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
 )
 
 type (
@@ -17,10 +25,24 @@ type (
 		Payment(ctx context.Context, req presenter.OrderAccountRequest) error
 		PaymentLimit(ctx context.Context, req presenter.OrderAccountRequest) error
 		Delete(ctx context.Context, req presenter.AccountNumberRequest) error
+		Reverse(ctx context.Context, req presenter.ReversalRequest) ([]presenter.TransactionResponse, error)
+		Refund(ctx context.Context, req presenter.RefundRequest) (*presenter.TransactionResponse, error)
+		ActivateAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error)
+		FreezeAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error)
+		UnfreezeAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error)
+		CloseAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error)
+		AdjustLimit(ctx context.Context, req presenter.AdjustLimitRequest) (*presenter.AccountResponse, error)
+		CashDeposit(ctx context.Context, req presenter.OrderAccountRequest) error
+		CashWithdraw(ctx context.Context, req presenter.OrderAccountRequest) error
 	}
 	Reader interface {
 		FindByAcoount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error)
 		FindByCustomer(ctx context.Context, req presenter.AccountCustomerIDRequest) (*presenter.AccountResponse, error)
+		ListByCustomer(ctx context.Context) ([]presenter.AccountResponse, error)
+		Statement(ctx context.Context, req presenter.StatementRequest) (*presenter.StatementResponse, error)
+		Export(ctx context.Context, req presenter.ExportStatementRequest, w io.Writer) error
+		Products(ctx context.Context) []presenter.ProductResponse
+		InspectAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error)
 	}
 
 	AccountUseCase interface {
@@ -29,22 +51,33 @@ type (
 	}
 
 	accountUseCase struct {
-		logger *utils.Logger
-		repo   repositories.AccountRepository
+		logger    *utils.Logger
+		repo      repositories.AccountRepository
+		ledger    repositories.TransactionRepository
+		audit     audit.Auditor
+		customers directory.CustomerDirectory
+		catalog   *domain.Catalog
+		numbers   *genrand.AccountNumberAllocator
 	}
 )
 
 // FindByCustomer implements AccountUseCase.
 func (auc *accountUseCase) FindByCustomer(ctx context.Context, req presenter.AccountCustomerIDRequest) (*presenter.AccountResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.FindByCustomer")
+	defer span.End()
 	if err := utils.ValidateStruct(req); err != nil {
-		auc.logger.Errorf("error validating request: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
 		return &presenter.AccountResponse{}, err
 	}
 
+	if tk, ok := utils.ClaimsFromContext(ctx); !ok || tk.ID != req.CustomerID {
+		return &presenter.AccountResponse{}, domain.ErrAccountForbidden
+	}
+
 	acc, err := auc.repo.GetCustomerID(ctx, req.CustomerID)
 
 	if err != nil {
-		auc.logger.Errorf("error getting account: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error getting account: %v", err)
 		return &presenter.AccountResponse{}, err
 	}
 	return &presenter.AccountResponse{
@@ -52,20 +85,38 @@ func (auc *accountUseCase) FindByCustomer(ctx context.Context, req presenter.Acc
 		AccountType:   acc.AccountType,
 		Balance:       acc.Balance,
 		Limit:         acc.Limit,
+		Currency:      acc.Currency(),
+		Status:        string(acc.Status),
 		Name:          acc.Name,
 	}, nil
 }
 
 // Transfer implements AccountUseCase.
-func (auc *accountUseCase) Transfer(ctx context.Context, req presenter.TransferAccountRequest) error {
+func (auc *accountUseCase) Transfer(ctx context.Context, req presenter.TransferAccountRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Transfer")
+	defer span.End()
+	defer func() {
+		tracing.Fail(span, err)
+		recordOperation("transfer", req.Amount, req.Currency, err)
+	}()
 
 	if err := utils.ValidateStruct(req); err != nil {
-		auc.logger.Errorf("error validating request: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
 		return err
 	}
 
-	if err := auc.repo.Transfer(ctx, req.Amount, req.FromAccountNumber, req.ToAccountNumber); err != nil {
-		auc.logger.Errorf("error depositing account: %v", err)
+	if err := auc.authorize(ctx, req.FromAccountNumber, "transfer"); err != nil {
+		return err
+	}
+
+	amount, err := parseAmount(req.Amount, req.Currency)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error parsing amount: %v", err)
+		return err
+	}
+
+	if err := auc.repo.Transfer(ctx, amount, req.FromAccountNumber, req.ToAccountNumber); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error depositing account: %v", err)
 		return err
 	}
 	return nil
@@ -73,45 +124,117 @@ func (auc *accountUseCase) Transfer(ctx context.Context, req presenter.TransferA
 
 // Create implements AccountUseCase.
 func (auc *accountUseCase) Create(ctx context.Context, req presenter.CreateAccountRequest) error {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Create")
+	defer span.End()
 
 	if err := utils.ValidateStruct(req); err != nil {
-		auc.logger.Errorf("error validating request: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
+	}
+
+	product, err := auc.catalog.Lookup(req.AccountType)
+	if err != nil {
+		return err
+	}
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = product.Currencies[0]
+	}
+
+	limit, err := domain.ParseMoney(orZero(req.Limit), currency)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error parsing limit: %v", err)
+		return err
+	}
+	if limit.IsNegative() {
+		return domain.ErrInvalidAmount
+	}
+
+	cr, err := auc.customers.Lookup(ctx, req.CustomerID)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error resolving customer: %v", err)
 		return err
 	}
+	cr.Name = req.Name
 
-	if err := auc.repo.CreateAccount(ctx, req.CustomerID, req.Name, req.AccountType, req.Limit); err != nil {
-		auc.logger.Errorf("error creating account: %v", err)
+	acc, err := domain.NewAccount(&cr, product, currency, limit)
+	if err != nil {
+		return err
+	}
+
+	_, err = auc.numbers.Allocate(product.Prefix, product.Length, func(number string) error {
+		acc.AccountNumber = number
+		return auc.repo.CreateAccount(ctx, acc)
+	})
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error creating account: %v", err)
 		return err
 	}
 
 	return nil
 }
 
-// Delete implements AccountUseCase.
+// Products implements AccountUseCase.
+func (auc *accountUseCase) Products(ctx context.Context) []presenter.ProductResponse {
+	_, span := tracing.Start(ctx, "accountUseCase.Products")
+	defer span.End()
+	products := auc.catalog.Products()
+	res := make([]presenter.ProductResponse, 0, len(products))
+	for _, p := range products {
+		res = append(res, presenter.NewProductResponse(p))
+	}
+	return res
+}
+
+// Delete implements AccountUseCase. Accounts are never removed: the holder
+// deleting an account closes it, which needs the account to be settled.
 func (auc *accountUseCase) Delete(ctx context.Context, req presenter.AccountNumberRequest) error {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Delete")
+	defer span.End()
 
 	if err := utils.ValidateStruct(req); err != nil {
-		auc.logger.Errorf("error validating request: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
 		return err
 	}
 
-	if err := auc.repo.DeleteAccount(ctx, req.AccountNumber); err != nil {
-		auc.logger.Errorf("error deleting account: %v", err)
+	if err := auc.authorize(ctx, req.AccountNumber, "delete"); err != nil {
+		return err
+	}
+
+	if _, err := auc.repo.ChangeStatus(ctx, req.AccountNumber, (*domain.Account).Close); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error closing account: %v", err)
 		return err
 	}
 	return nil
 }
 
-// Deposit implements AccountUseCase.
-func (auc *accountUseCase) Deposit(ctx context.Context, req presenter.OrderAccountRequest) error {
+// Deposit implements AccountUseCase. Customers deposit into their own
+// accounts only; a deposit into anyone's account is CashDeposit, at a teller.
+func (auc *accountUseCase) Deposit(ctx context.Context, req presenter.OrderAccountRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Deposit")
+	defer span.End()
+	defer func() {
+		tracing.Fail(span, err)
+		recordOperation("deposit", req.Amount, req.Currency, err)
+	}()
 
 	if err := utils.ValidateStruct(req); err != nil {
-		auc.logger.Errorf("error validating request: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
+	}
+
+	if err := auc.authorize(ctx, req.AccountNumber, "deposit"); err != nil {
+		return err
+	}
+
+	amount, err := parseAmount(req.Amount, req.Currency)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error parsing amount: %v", err)
 		return err
 	}
 
-	if err := auc.repo.Deposit(ctx, req.Amount, req.AccountNumber); err != nil {
-		auc.logger.Errorf("error depositing account: %v", err)
+	if err := auc.repo.Deposit(ctx, amount, req.AccountNumber); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error depositing account: %v", err)
 		return err
 	}
 	return nil
@@ -119,16 +242,22 @@ func (auc *accountUseCase) Deposit(ctx context.Context, req presenter.OrderAccou
 
 // FindByAcoount implements AccountUseCase.
 func (auc *accountUseCase) FindByAcoount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.FindByAcoount")
+	defer span.End()
 
 	if err := utils.ValidateStruct(req); err != nil {
-		auc.logger.Errorf("error validating request: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return &presenter.AccountResponse{}, err
+	}
+
+	if err := auc.authorize(ctx, req.AccountNumber, "read"); err != nil {
 		return &presenter.AccountResponse{}, err
 	}
 
 	acc, err := auc.repo.GetAccountNumber(ctx, req.AccountNumber)
 
 	if err != nil {
-		auc.logger.Errorf("error getting account: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error getting account: %v", err)
 		return &presenter.AccountResponse{}, err
 	}
 	return &presenter.AccountResponse{
@@ -136,20 +265,38 @@ func (auc *accountUseCase) FindByAcoount(ctx context.Context, req presenter.Acco
 		AccountType:   acc.AccountType,
 		Balance:       acc.Balance,
 		Limit:         acc.Limit,
+		Currency:      acc.Currency(),
+		Status:        string(acc.Status),
 		Name:          acc.Name,
 	}, nil
 }
 
 // Payment implements AccountUseCase.
-func (auc *accountUseCase) Payment(ctx context.Context, req presenter.OrderAccountRequest) error {
+func (auc *accountUseCase) Payment(ctx context.Context, req presenter.OrderAccountRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Payment")
+	defer span.End()
+	defer func() {
+		tracing.Fail(span, err)
+		recordOperation("payment", req.Amount, req.Currency, err)
+	}()
 
 	if err := utils.ValidateStruct(req); err != nil {
-		auc.logger.Errorf("error validating request: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
 		return err
 	}
 
-	if err := auc.repo.Payment(ctx, req.Amount, req.AccountNumber); err != nil {
-		auc.logger.Errorf("error payment account: %v", err)
+	if err := auc.authorize(ctx, req.AccountNumber, "payment"); err != nil {
+		return err
+	}
+
+	amount, err := parseAmount(req.Amount, req.Currency)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error parsing amount: %v", err)
+		return err
+	}
+
+	if err := auc.repo.Payment(ctx, amount, req.AccountNumber); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error payment account: %v", err)
 		return err
 	}
 
@@ -157,38 +304,122 @@ func (auc *accountUseCase) Payment(ctx context.Context, req presenter.OrderAccou
 }
 
 // PaymentLimit implements AccountUseCase.
-func (auc *accountUseCase) PaymentLimit(ctx context.Context, req presenter.OrderAccountRequest) error {
+func (auc *accountUseCase) PaymentLimit(ctx context.Context, req presenter.OrderAccountRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.PaymentLimit")
+	defer span.End()
+	defer func() {
+		tracing.Fail(span, err)
+		recordOperation("payment_limit", req.Amount, req.Currency, err)
+	}()
 
 	if err := utils.ValidateStruct(req); err != nil {
-		auc.logger.Errorf("error validating request: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
 		return err
 	}
 
-	if err := auc.repo.PaymentLimit(ctx, req.Amount, req.AccountNumber); err != nil {
-		auc.logger.Errorf("error payment limit account: %v", err)
+	if err := auc.authorize(ctx, req.AccountNumber, "payment_limit"); err != nil {
+		return err
+	}
+
+	amount, err := parseAmount(req.Amount, req.Currency)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error parsing amount: %v", err)
+		return err
+	}
+
+	if err := auc.repo.PaymentLimit(ctx, amount, req.AccountNumber); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error payment limit account: %v", err)
 		return err
 	}
 	return nil
 }
 
 // Withdraw implements AccountUseCase.
-func (auc *accountUseCase) Withdraw(ctx context.Context, req presenter.OrderAccountRequest) error {
+func (auc *accountUseCase) Withdraw(ctx context.Context, req presenter.OrderAccountRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Withdraw")
+	defer span.End()
+	defer func() {
+		tracing.Fail(span, err)
+		recordOperation("withdraw", req.Amount, req.Currency, err)
+	}()
 
 	if err := utils.ValidateStruct(req); err != nil {
-		auc.logger.Errorf("error validating request: %v", err)
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
+	}
+
+	if err := auc.authorize(ctx, req.AccountNumber, "withdraw"); err != nil {
+		return err
+	}
+
+	amount, err := parseAmount(req.Amount, req.Currency)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error parsing amount: %v", err)
 		return err
 	}
 
-	if err := auc.repo.Withdraw(ctx, req.Amount, req.AccountNumber); err != nil {
-		auc.logger.Errorf("error withdrawing account: %v", err)
+	if err := auc.repo.Withdraw(ctx, amount, req.AccountNumber); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error withdrawing account: %v", err)
 		return err
 	}
 	return nil
 }
 
-func NewAccountUseCase(repo repositories.AccountRepository) AccountUseCase {
+// authorize checks that the authenticated customer in ctx owns accountNumber.
+// Denials are written to the audit log.
+func (auc *accountUseCase) authorize(ctx context.Context, accountNumber, action string) error {
+	tk, ok := utils.ClaimsFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthenticated
+	}
+
+	acc, err := auc.repo.GetAccountNumber(ctx, accountNumber)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error getting account: %v", err)
+		return err
+	}
+
+	if acc.CustomerID != tk.ID {
+		auc.audit.Record(ctx, audit.Event{
+			Action:     "account." + action,
+			CustomerID: tk.ID,
+			Resource:   accountNumber,
+			Outcome:    audit.OutcomeDenied,
+			Detail:     domain.ErrAccountForbidden.Error(),
+		})
+		return domain.ErrAccountForbidden
+	}
+	return nil
+}
+
+// parseAmount converts a request amount into Money, rejecting anything that
+// is not strictly positive.
+func parseAmount(amount json.Number, currency string) (domain.Money, error) {
+	m, err := domain.ParseMoney(amount.String(), currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if !m.IsPositive() {
+		return domain.Money{}, domain.ErrInvalidAmount
+	}
+	return m, nil
+}
+
+func orZero(n json.Number) string {
+	if n == "" {
+		return "0"
+	}
+	return n.String()
+}
+
+func NewAccountUseCase(repo repositories.AccountRepository, ledger repositories.TransactionRepository, auditor audit.Auditor, customers directory.CustomerDirectory, catalog *domain.Catalog, numbers *genrand.AccountNumberAllocator) AccountUseCase {
 	return &accountUseCase{
-		logger: utils.NewLogger("usecaseAccount"),
-		repo:   repo,
+		logger:    utils.NewLogger("usecaseAccount"),
+		repo:      repo,
+		ledger:    ledger,
+		audit:     auditor,
+		customers: customers,
+		catalog:   catalog,
+		numbers:   numbers,
 	}
 }
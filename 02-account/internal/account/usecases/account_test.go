@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/account/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// fakeAccounts serves accounts from memory and remembers the deposits it
+// was asked to make.
+type fakeAccounts struct {
+	repositories.AccountRepository
+	accounts map[string]*domain.Account
+	deposits []string
+}
+
+func (f *fakeAccounts) GetAccountNumber(ctx context.Context, accountNumber string) (*domain.Account, error) {
+	acc, ok := f.accounts[accountNumber]
+	if !ok {
+		return &domain.Account{}, sql.ErrNoRows
+	}
+	return acc, nil
+}
+
+func (f *fakeAccounts) Deposit(ctx context.Context, amount domain.Money, accountNumber string) error {
+	f.deposits = append(f.deposits, accountNumber)
+	return nil
+}
+
+// fakeAuditor keeps the events recorded.
+type fakeAuditor struct {
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+func (f *fakeAuditor) Record(ctx context.Context, e audit.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, e)
+}
+
+func asCustomer(id string) context.Context {
+	return utils.ContextWithClaims(context.Background(), utils.Claims{ID: id})
+}
+
+func TestDepositRequiresOwnership(t *testing.T) {
+	repo := &fakeAccounts{accounts: map[string]*domain.Account{
+		"0001": {AccountNumber: "0001", CustomerID: "alice", Status: domain.AccountActive, Balance: domain.NewMoney(0, "BRL")},
+	}}
+	auditor := &fakeAuditor{}
+	uc := NewAccountUseCase(repo, nil, auditor, nil, nil, nil)
+	req := presenter.OrderAccountRequest{AccountNumber: "0001", Amount: "10.00", Currency: "BRL"}
+
+	if err := uc.Deposit(asCustomer("mallory"), req); !errors.Is(err, domain.ErrAccountForbidden) {
+		t.Fatalf("deposit by a non-owner: err = %v, want ErrAccountForbidden", err)
+	}
+	if len(auditor.events) != 1 || auditor.events[0].Action != "account.deposit" || auditor.events[0].Outcome != audit.OutcomeDenied {
+		t.Fatalf("audit events = %+v, want one denied account.deposit", auditor.events)
+	}
+
+	req.AccountNumber = "9999"
+	if err := uc.Deposit(asCustomer("mallory"), req); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("deposit to an unknown account: err = %v, want sql.ErrNoRows", err)
+	}
+	if len(repo.deposits) != 0 {
+		t.Fatalf("deposits made for callers who do not own the account: %v", repo.deposits)
+	}
+
+	req.AccountNumber = "0001"
+	if err := uc.Deposit(asCustomer("alice"), req); err != nil {
+		t.Fatalf("deposit by the owner: %v", err)
+	}
+	if len(repo.deposits) != 1 {
+		t.Fatalf("%d deposits made, want the owner's", len(repo.deposits))
+	}
+}
@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// The operations below serve staff rather than account holders: they skip
+// the ownership check, so routes must restrict them by role, and each one is
+// written to the audit log with the operator's identity.
+
+// InspectAccount implements AccountUseCase.
+func (auc *accountUseCase) InspectAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.InspectAccount")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	acc, err := auc.repo.GetAccountNumber(ctx, req.AccountNumber)
+	auc.recordOperator(ctx, "account.inspect", req.AccountNumber, err)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error getting account: %v", err)
+		return nil, err
+	}
+	return staffAccountResponse(acc), nil
+}
+
+// AdjustLimit implements AccountUseCase. The new limit is read in the
+// account's currency and may not exceed its product's maximum.
+func (auc *accountUseCase) AdjustLimit(ctx context.Context, req presenter.AdjustLimitRequest) (*presenter.AccountResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.AdjustLimit")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	acc, err := auc.repo.ChangeLimit(ctx, req.AccountNumber, func(acc *domain.Account) error {
+		line, err := domain.ParseMoney(req.Limit.String(), acc.Currency())
+		if err != nil {
+			return err
+		}
+		product, err := auc.catalog.Lookup(acc.AccountType)
+		if err != nil {
+			return err
+		}
+		_, max, err := product.Limits(acc.Currency())
+		if err != nil {
+			return err
+		}
+		return acc.SetCreditLimit(line, max)
+	})
+	auc.recordOperator(ctx, "account.limit", req.AccountNumber, err)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error adjusting account limit: %v", err)
+		return nil, err
+	}
+	return staffAccountResponse(acc), nil
+}
+
+// CashDeposit implements AccountUseCase. A teller posts cash handed over at
+// the counter.
+func (auc *accountUseCase) CashDeposit(ctx context.Context, req presenter.OrderAccountRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.CashDeposit")
+	defer span.End()
+	defer func() {
+		tracing.Fail(span, err)
+		recordOperation("cash_deposit", req.Amount, req.Currency, err)
+	}()
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
+	}
+
+	amount, err := parseAmount(req.Amount, req.Currency)
+	if err == nil {
+		err = auc.repo.Deposit(ctx, amount, req.AccountNumber)
+	}
+	auc.recordOperator(ctx, "account.cash_deposit", req.AccountNumber, err)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error depositing cash: %v", err)
+	}
+	return err
+}
+
+// CashWithdraw implements AccountUseCase. A teller pays out cash to the
+// account holder, who has identified themselves at the counter.
+func (auc *accountUseCase) CashWithdraw(ctx context.Context, req presenter.OrderAccountRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.CashWithdraw")
+	defer span.End()
+	defer func() {
+		tracing.Fail(span, err)
+		recordOperation("cash_withdraw", req.Amount, req.Currency, err)
+	}()
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
+	}
+
+	amount, err := parseAmount(req.Amount, req.Currency)
+	if err == nil {
+		err = auc.repo.Withdraw(ctx, amount, req.AccountNumber)
+	}
+	auc.recordOperator(ctx, "account.cash_withdraw", req.AccountNumber, err)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error withdrawing cash: %v", err)
+	}
+	return err
+}
+
+func staffAccountResponse(acc *domain.Account) *presenter.AccountResponse {
+	return &presenter.AccountResponse{
+		AccountNumber: acc.AccountNumber,
+		AccountType:   acc.AccountType,
+		CustomerID:    acc.CustomerID,
+		Balance:       acc.Balance,
+		Limit:         acc.Limit,
+		Currency:      acc.Currency(),
+		Status:        string(acc.Status),
+		Name:          acc.Name,
+	}
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// ActivateAccount implements AccountUseCase. Accounts are opened pending;
+// this is where the back office lets them take business.
+func (auc *accountUseCase) ActivateAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.ActivateAccount")
+	defer span.End()
+	return auc.changeStatus(ctx, req, "account.activate", (*domain.Account).Activate)
+}
+
+// FreezeAccount implements AccountUseCase. Like the other back-office
+// operations it does not check ownership.
+func (auc *accountUseCase) FreezeAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.FreezeAccount")
+	defer span.End()
+	return auc.changeStatus(ctx, req, "account.freeze", (*domain.Account).Freeze)
+}
+
+// UnfreezeAccount implements AccountUseCase.
+func (auc *accountUseCase) UnfreezeAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.UnfreezeAccount")
+	defer span.End()
+	return auc.changeStatus(ctx, req, "account.unfreeze", (*domain.Account).Unfreeze)
+}
+
+// CloseAccount implements AccountUseCase.
+func (auc *accountUseCase) CloseAccount(ctx context.Context, req presenter.AccountNumberRequest) (*presenter.AccountResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.CloseAccount")
+	defer span.End()
+	return auc.changeStatus(ctx, req, "account.close", (*domain.Account).Close)
+}
+
+func (auc *accountUseCase) changeStatus(ctx context.Context, req presenter.AccountNumberRequest, action string, apply func(*domain.Account) error) (*presenter.AccountResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	acc, err := auc.repo.ChangeStatus(ctx, req.AccountNumber, apply)
+	auc.recordOperator(ctx, action, req.AccountNumber, err)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error changing account status: %v", err)
+		return nil, err
+	}
+
+	return &presenter.AccountResponse{
+		AccountNumber: acc.AccountNumber,
+		AccountType:   acc.AccountType,
+		Balance:       acc.Balance,
+		Limit:         acc.Limit,
+		Currency:      acc.Currency(),
+		Status:        string(acc.Status),
+		Name:          acc.Name,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/metrics"
+	"github.com/asaskevich/govalidator"
+)
+
+// operations feeds account_operations_total and account_operation_amount.
+var operations = metrics.NewOperations("account", errorType)
+
+// errorTypes names the failures worth telling apart on a dashboard; any
+// other error is counted as "other".
+var errorTypes = []struct {
+	err  error
+	name string
+}{
+	{domain.ErrInsufficientFunds, "insufficient_funds"},
+	{domain.ErrDebitInsufficient, "insufficient_funds"},
+	{domain.ErrPaymentInsufficient, "insufficient_funds"},
+	{domain.ErrWithdrawalInsufficient, "insufficient_funds"},
+	{domain.ErrTransferInsufficient, "insufficient_funds"},
+	{domain.ErrDepositLimitExceeded, "limit_exceeded"},
+	{domain.ErrPaymentLimitExceeded, "limit_exceeded"},
+	{domain.ErrTransferSameAccount, "same_account"},
+	{domain.ErrInvalidAmount, "invalid_amount"},
+	{domain.ErrMoneyOverflow, "invalid_amount"},
+	{domain.ErrInvalidCurrency, "invalid_currency"},
+	{domain.ErrCurrencyMismatch, "currency_mismatch"},
+	{domain.ErrUnauthenticated, "unauthenticated"},
+	{domain.ErrAccountForbidden, "forbidden"},
+	{domain.ErrAccountPending, "account_pending"},
+	{domain.ErrAccountFrozen, "account_frozen"},
+	{domain.ErrAccountClosed, "account_closed"},
+	{sql.ErrNoRows, "not_found"},
+}
+
+func errorType(err error) string {
+	var verr govalidator.Errors
+	if errors.As(err, &verr) {
+		return "invalid_request"
+	}
+	for _, t := range errorTypes {
+		if errors.Is(err, t.err) {
+			return t.name
+		}
+	}
+	return "other"
+}
+
+// recordOperation counts a money movement once it returned err. Callers
+// defer it with their named error result.
+func recordOperation(operation string, amount json.Number, currency string, err error) {
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	f, _ := amount.Float64()
+	operations.Record(operation, strings.ToUpper(currency), f, err)
+}
@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// Reverse implements AccountUseCase. It is a back-office operation: callers
+// are expected to be authorized operators, not account holders.
+func (auc *accountUseCase) Reverse(ctx context.Context, req presenter.ReversalRequest) ([]presenter.TransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Reverse")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	posted, err := auc.repo.Reverse(ctx, req.TransactionID)
+	auc.recordOperator(ctx, "transaction.reverse", req.TransactionID, err)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error reversing transaction: %v", err)
+		return nil, err
+	}
+
+	res := make([]presenter.TransactionResponse, 0, len(posted))
+	for _, tx := range posted {
+		res = append(res, presenter.NewTransactionResponse(tx))
+	}
+	return res, nil
+}
+
+// Refund implements AccountUseCase.
+func (auc *accountUseCase) Refund(ctx context.Context, req presenter.RefundRequest) (*presenter.TransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Refund")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	orig, err := auc.ledger.GetTransactionID(ctx, req.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = orig.Amount.Currency()
+	}
+	amount, err := parseAmount(req.Amount, currency)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error parsing amount: %v", err)
+		return nil, err
+	}
+
+	posted, err := auc.repo.Refund(ctx, req.TransactionID, amount)
+	auc.recordOperator(ctx, "transaction.refund", req.TransactionID, err)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error refunding transaction: %v", err)
+		return nil, err
+	}
+
+	res := presenter.NewTransactionResponse(*posted)
+	return &res, nil
+}
+
+// recordOperator writes a back-office action to the audit log together with
+// the identity of whoever performed it.
+func (auc *accountUseCase) recordOperator(ctx context.Context, action, resource string, err error) {
+	e := audit.Event{
+		Action:   action,
+		Resource: resource,
+		Outcome:  audit.OutcomeAllowed,
+	}
+	if tk, ok := utils.ClaimsFromContext(ctx); ok {
+		e.Operator, e.Role = tk.ID, tk.Role
+	}
+	if err != nil {
+		e.Outcome = audit.OutcomeFailed
+		e.Detail = err.Error()
+	}
+	auc.audit.Record(ctx, e)
+}
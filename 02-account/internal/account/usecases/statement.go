@@ -0,0 +1,262 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/account/internal/statement"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+const (
+	defaultStatementLimit = 50
+	maxStatementLimit     = 200
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid statement cursor")
+	ErrInvalidDate   = errors.New("invalid date, use YYYY-MM-DD or RFC 3339")
+	ErrInvalidType   = errors.New("invalid transaction type")
+)
+
+// ListByCustomer implements AccountUseCase.
+func (auc *accountUseCase) ListByCustomer(ctx context.Context) ([]presenter.AccountResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.ListByCustomer")
+	defer span.End()
+	tk, ok := utils.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	accs, err := auc.repo.ListCustomerAccounts(ctx, tk.ID)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error listing accounts: %v", err)
+		return nil, err
+	}
+
+	res := make([]presenter.AccountResponse, 0, len(accs))
+	for _, acc := range accs {
+		res = append(res, presenter.AccountResponse{
+			AccountNumber: acc.AccountNumber,
+			AccountType:   acc.AccountType,
+			Balance:       acc.Balance,
+			Limit:         acc.Limit,
+			Currency:      acc.Currency(),
+			Status:        string(acc.Status),
+			Name:          acc.Name,
+		})
+	}
+	return res, nil
+}
+
+// Statement implements AccountUseCase. Lines are returned oldest first; each
+// carries the account balance right after it was posted.
+func (auc *accountUseCase) Statement(ctx context.Context, req presenter.StatementRequest) (*presenter.StatementResponse, error) {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Statement")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	if err := auc.authorize(ctx, req.AccountNumber, "statement"); err != nil {
+		return nil, err
+	}
+
+	filter, err := statementFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := filter.Limit
+	filter.Limit++
+
+	txs, err := auc.ledger.ListTransactions(ctx, filter)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error listing transactions: %v", err)
+		return nil, err
+	}
+
+	res := &presenter.StatementResponse{
+		AccountNumber: req.AccountNumber,
+		Lines:         make([]presenter.StatementLine, 0, pageSize),
+	}
+	if len(txs) > pageSize {
+		txs = txs[:pageSize]
+		last := txs[len(txs)-1]
+		res.NextCursor = encodeCursor(domain.StatementCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	for _, tx := range txs {
+		direction := "credit"
+		if tx.IsDebit() {
+			direction = "debit"
+		}
+		res.Currency = tx.Amount.Currency()
+		res.Lines = append(res.Lines, presenter.StatementLine{
+			ID:             tx.ID,
+			Type:           string(tx.Type),
+			Direction:      direction,
+			Amount:         tx.Amount,
+			RunningBalance: tx.BalanceAfter,
+			Counterparty:   tx.Counterparty,
+			CreatedAt:      tx.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func statementFilter(req presenter.StatementRequest) (domain.StatementFilter, error) {
+	filter := domain.StatementFilter{
+		AccountNumber: req.AccountNumber,
+		Limit:         req.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultStatementLimit
+	}
+	if filter.Limit > maxStatementLimit {
+		filter.Limit = maxStatementLimit
+	}
+
+	var err error
+	if filter.From, err = parseDate(req.From, false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseDate(req.To, true); err != nil {
+		return filter, err
+	}
+
+	if req.Types != "" {
+		for _, t := range strings.Split(req.Types, ",") {
+			tt, err := parseTransactionType(strings.TrimSpace(t))
+			if err != nil {
+				return filter, err
+			}
+			filter.Types = append(filter.Types, tt)
+		}
+	}
+
+	if req.Cursor != "" {
+		c, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = &c
+	}
+	return filter, nil
+}
+
+// parseDate accepts RFC 3339 timestamps or plain dates. A plain date used as
+// an upper bound covers the whole day.
+func parseDate(s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func parseTransactionType(s string) (domain.TransactionType, error) {
+	for _, t := range []domain.TransactionType{
+		domain.Deposit,
+		domain.Transfer,
+		domain.Withdraw,
+		domain.Payment,
+		domain.PaymentLimit,
+		domain.Refund,
+		domain.Reversal,
+	} {
+		if strings.EqualFold(s, string(t)) {
+			return t, nil
+		}
+	}
+	return "", ErrInvalidType
+}
+
+func encodeCursor(c domain.StatementCursor) string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (domain.StatementCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return domain.StatementCursor{}, ErrInvalidCursor
+	}
+	ns, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return domain.StatementCursor{}, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(ns, 10, 64)
+	if err != nil {
+		return domain.StatementCursor{}, ErrInvalidCursor
+	}
+	return domain.StatementCursor{CreatedAt: time.Unix(0, n).UTC(), ID: id}, nil
+}
+
+// Export implements AccountUseCase. Nothing is written to w unless the
+// request is valid and the caller owns the account.
+func (auc *accountUseCase) Export(ctx context.Context, req presenter.ExportStatementRequest, w io.Writer) error {
+	ctx, span := tracing.Start(ctx, "accountUseCase.Export")
+	defer span.End()
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return err
+	}
+
+	format, err := statement.ParseFormat(req.Format)
+	if err != nil {
+		return err
+	}
+	from, err := parseDate(req.From, false)
+	if err != nil {
+		return err
+	}
+	to, err := parseDate(req.To, true)
+	if err != nil {
+		return err
+	}
+
+	if err := auc.authorize(ctx, req.AccountNumber, "export"); err != nil {
+		return err
+	}
+
+	acc, err := auc.repo.GetAccountNumber(ctx, req.AccountNumber)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error getting account: %v", err)
+		return err
+	}
+
+	err = statement.Export(ctx, auc.ledger, statement.Request{
+		Format:        format,
+		AccountNumber: acc.AccountNumber,
+		AccountType:   acc.AccountType,
+		Name:          acc.Name,
+		Currency:      acc.Currency(),
+		OpenedAt:      acc.CreatedAt,
+		From:          from,
+		To:            to,
+		GeneratedAt:   time.Now().UTC().Truncate(time.Second),
+		Balance:       acc.Balance,
+	}, w)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error exporting statement: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/account/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+)
+
+// TestOppositeTransfersDoNotDeadlock runs transfers both ways between two
+// accounts at once, through the real repository, against a database that
+// detects deadlocks the way Postgres does. None may fail, and no money may
+// appear or vanish.
+func TestOppositeTransfersDoNotDeadlock(t *testing.T) {
+	bank := newFakeBank(map[string]int64{"0001": 100000, "0002": 100000})
+	db := sql.OpenDB(bank)
+	defer db.Close()
+	uc := NewAccountUseCase(repositories.NewAccountRepository(db), repositories.NewTransactionRepository(db), &fakeAuditor{}, nil, nil, nil)
+	ctx := asCustomer("alice")
+
+	const perDirection = 25
+	errs := make(chan error, 2*perDirection)
+	var wg sync.WaitGroup
+	for i := 0; i < perDirection; i++ {
+		for _, pair := range [][2]string{{"0001", "0002"}, {"0002", "0001"}} {
+			wg.Add(1)
+			go func(from, to string) {
+				defer wg.Done()
+				errs <- uc.Transfer(ctx, presenter.TransferAccountRequest{FromAccountNumber: from, ToAccountNumber: to, Amount: "1.00", Currency: "BRL"})
+			}(pair[0], pair[1])
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("transfer failed: %v", err)
+		}
+	}
+	if a, b := bank.balance("0001"), bank.balance("0002"); a != 100000 || b != 100000 {
+		t.Errorf("balances = %d and %d, want 100000 each after as many transfers each way", a, b)
+	}
+	if n := bank.entries(); n != 4*perDirection {
+		t.Errorf("%d ledger entries, want two per transfer", n)
+	}
+}
+
+// fakeBank is an in-memory accounts table and ledger speaking just the
+// statements the account repository issues. Rows read FOR UPDATE stay locked
+// until the transaction ends; a transaction that would wait on itself
+// through a chain of lock holders fails with a deadlock error. Writes are
+// applied on commit.
+type fakeBank struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	rows    map[string][]driver.Value
+	ledger  int
+	owner   map[string]*bankTx
+	waiting map[*bankTx]string
+}
+
+type bankTx struct {
+	bank    *fakeBank
+	updates map[string][]driver.Value
+	entries int
+	locked  []string
+}
+
+var errDeadlock = errors.New("pq: deadlock detected")
+
+func newFakeBank(balances map[string]int64) *fakeBank {
+	b := &fakeBank{
+		rows:    make(map[string][]driver.Value),
+		owner:   make(map[string]*bankTx),
+		waiting: make(map[*bankTx]string),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	now := time.Now().UTC()
+	for number, balance := range balances {
+		b.rows[number] = []driver.Value{number, "checking", "alice", "Alice", balance, int64(0), int64(0), "BRL", "active", now, now}
+	}
+	return b
+}
+
+func (b *fakeBank) balance(number string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rows[number][4].(int64)
+}
+
+func (b *fakeBank) entries() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.ledger
+}
+
+// lock takes the row lock on number for tx, waiting for its holder.
+func (b *fakeBank) lock(tx *bankTx, number string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		holder := b.owner[number]
+		if holder == nil || holder == tx {
+			break
+		}
+		for h := holder; h != nil; {
+			if h == tx {
+				return errDeadlock
+			}
+			next, ok := b.waiting[h]
+			if !ok {
+				break
+			}
+			h = b.owner[next]
+		}
+		b.waiting[tx] = number
+		b.cond.Wait()
+		delete(b.waiting, tx)
+	}
+	if b.owner[number] == nil {
+		b.owner[number] = tx
+		tx.locked = append(tx.locked, number)
+	}
+	return nil
+}
+
+func (b *fakeBank) row(number string) []driver.Value {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	row, ok := b.rows[number]
+	if !ok {
+		return nil
+	}
+	return append([]driver.Value(nil), row...)
+}
+
+func (b *fakeBank) Connect(context.Context) (driver.Conn, error) { return &bankConn{bank: b}, nil }
+func (b *fakeBank) Driver() driver.Driver                        { return nil }
+
+type bankConn struct {
+	bank *fakeBank
+	tx   *bankTx
+}
+
+func (c *bankConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *bankConn) Close() error                        { return nil }
+func (c *bankConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *bankConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.tx = &bankTx{bank: c.bank, updates: make(map[string][]driver.Value)}
+	return c, nil
+}
+
+func (c *bankConn) Commit() error {
+	b := c.bank
+	b.mu.Lock()
+	for number, row := range c.tx.updates {
+		b.rows[number] = row
+	}
+	b.ledger += c.tx.entries
+	b.mu.Unlock()
+	return c.end()
+}
+
+func (c *bankConn) Rollback() error { return c.end() }
+
+func (c *bankConn) end() error {
+	b := c.bank
+	b.mu.Lock()
+	for _, number := range c.tx.locked {
+		delete(b.owner, number)
+	}
+	b.cond.Broadcast()
+	b.mu.Unlock()
+	c.tx = nil
+	return nil
+}
+
+func (c *bankConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	number := args[0].Value.(string)
+	if strings.HasSuffix(query, "FOR UPDATE") {
+		if c.tx == nil {
+			return nil, errors.New("FOR UPDATE outside a transaction")
+		}
+		if err := c.bank.lock(c.tx, number); err != nil {
+			return nil, err
+		}
+		// Hold the lock a moment, so concurrent transactions interleave.
+		time.Sleep(time.Millisecond)
+	}
+	rows := &accountRows{}
+	if row := c.bank.row(number); row != nil {
+		rows.values = [][]driver.Value{row}
+	}
+	return rows, nil
+}
+
+func (c *bankConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.tx == nil {
+		return nil, fmt.Errorf("%q outside a transaction", query)
+	}
+	switch {
+	case strings.HasPrefix(query, "UPDATE Accounts set balance"):
+		number := args[0].Value.(string)
+		row, ok := c.tx.updates[number]
+		if !ok {
+			row = c.bank.row(number)
+		}
+		row[4], row[5], row[10] = args[1].Value, args[2].Value, args[3].Value
+		c.tx.updates[number] = row
+	case strings.HasPrefix(query, "INSERT INTO transactions"):
+		c.tx.entries++
+	default:
+		return nil, fmt.Errorf("unexpected statement %q", query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type accountRows struct {
+	values [][]driver.Value
+}
+
+func (r *accountRows) Columns() []string {
+	return []string{"account_number", "account_type", "customer_id", "name", "balance", "acc_limit", "acc_reversal", "currency", "status", "created_at", "updated_at"}
+}
+
+func (r *accountRows) Close() error { return nil }
+
+func (r *accountRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	accrepo "github.com/adilsonmenechini/golabbank/account/internal/account/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type (
+	AuthorizationRepository interface {
+		Authorize(ctx context.Context, auth *domain.Authorization) error
+		Capture(ctx context.Context, id string, amount domain.Money) (*domain.Authorization, error)
+		Void(ctx context.Context, id string) (*domain.Authorization, error)
+		GetAuthorizationID(ctx context.Context, id string) (*domain.Authorization, error)
+		ExpirePending(ctx context.Context, now time.Time, limit int) (int, error)
+	}
+
+	authorizationRepository struct {
+		logger *utils.Logger
+		db     *sql.DB
+		ledger accrepo.TransactionRepository
+	}
+)
+
+// Authorize implements AuthorizationRepository. The hold on the account's
+// credit line and the authorization row are written in one transaction.
+func (ar *authorizationRepository) Authorize(ctx context.Context, auth *domain.Authorization) error {
+	return accrepo.InTx(ctx, ar.db, func(tx *sql.Tx) error {
+		acc, err := accrepo.LockAccount(ctx, tx, auth.AccountNumber)
+		if err != nil {
+			return err
+		}
+		if err := acc.Hold(auth.Amount); err != nil {
+			return err
+		}
+		if err := accrepo.UpdateAccount(ctx, tx, acc); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, createAuthorization,
+			auth.ID,
+			auth.CardID,
+			auth.AccountNumber,
+			auth.Merchant,
+			auth.Amount.MinorUnits(),
+			auth.Captured.MinorUnits(),
+			auth.Amount.Currency(),
+			auth.Status,
+			auth.ExpiresAt,
+			auth.CreatedAt,
+			auth.UpdatedAt,
+		)
+		return err
+	})
+}
+
+// Capture implements AuthorizationRepository. The captured amount is posted
+// to the ledger as a Payment drawn on the credit line, and whatever was held
+// but not captured goes back to the credit line.
+func (ar *authorizationRepository) Capture(ctx context.Context, id string, amount domain.Money) (*domain.Authorization, error) {
+	var auth *domain.Authorization
+
+	err := accrepo.InTx(ctx, ar.db, func(tx *sql.Tx) error {
+		var err error
+		auth, err = lockAuthorization(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if amount.IsZero() {
+			// A full capture; give the zero value the hold's currency.
+			amount = domain.NewMoney(0, auth.Amount.Currency())
+		}
+		release, err := auth.Capture(amount, time.Now())
+		if err != nil {
+			return err
+		}
+
+		acc, err := accrepo.LockAccount(ctx, tx, auth.AccountNumber)
+		if err != nil {
+			return err
+		}
+		before := acc.Balance
+		if err := acc.ReleaseHold(release); err != nil {
+			return err
+		}
+		if err := accrepo.UpdateAccount(ctx, tx, acc); err != nil {
+			return err
+		}
+
+		entry := domain.NewTransaction(utils.GenerateUUID(), acc.AccountNumber, domain.Payment, auth.Captured, before, acc.Balance, auth.Merchant)
+		// The hold, and so the capture, was drawn on the credit line.
+		entry.CreditAmount = auth.Captured
+		if err := ar.ledger.WithTx(tx).CreateTransaction(ctx, entry); err != nil {
+			return err
+		}
+		auth.TransactionID = entry.ID
+
+		return updateAuthorization(ctx, tx, auth)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+// Void implements AuthorizationRepository.
+func (ar *authorizationRepository) Void(ctx context.Context, id string) (*domain.Authorization, error) {
+	var auth *domain.Authorization
+
+	err := accrepo.InTx(ctx, ar.db, func(tx *sql.Tx) error {
+		var err error
+		auth, err = lockAuthorization(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		release, err := auth.Void(time.Now())
+		if err != nil {
+			return err
+		}
+		return ar.release(ctx, tx, auth, release)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+// GetAuthorizationID implements AuthorizationRepository.
+func (ar *authorizationRepository) GetAuthorizationID(ctx context.Context, id string) (*domain.Authorization, error) {
+	return scanAuthorization(ar.db.QueryRowContext(ctx, getAuthorizationID, id))
+}
+
+// ExpirePending implements AuthorizationRepository. It releases the holds of
+// up to limit authorizations that were never captured before expiring, each
+// in its own transaction, and returns how many were expired.
+func (ar *authorizationRepository) ExpirePending(ctx context.Context, now time.Time, limit int) (int, error) {
+	rows, err := ar.db.QueryContext(ctx, listExpiredAuthorizations, now.UTC(), limit)
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		err := accrepo.InTx(ctx, ar.db, func(tx *sql.Tx) error {
+			auth, err := lockAuthorization(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			release, err := auth.Expire(now)
+			if err != nil {
+				return err
+			}
+			return ar.release(ctx, tx, auth, release)
+		})
+		switch err {
+		case nil:
+			expired++
+		case domain.ErrAuthorizationNotPending:
+			// Captured or voided since it was listed.
+		default:
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+// release gives amount back to the authorization's credit line and saves the
+// authorization's new status.
+func (ar *authorizationRepository) release(ctx context.Context, tx *sql.Tx, auth *domain.Authorization, amount domain.Money) error {
+	acc, err := accrepo.LockAccount(ctx, tx, auth.AccountNumber)
+	if err != nil {
+		return err
+	}
+	if err := acc.ReleaseHold(amount); err != nil {
+		return err
+	}
+	if err := accrepo.UpdateAccount(ctx, tx, acc); err != nil {
+		return err
+	}
+	return updateAuthorization(ctx, tx, auth)
+}
+
+func lockAuthorization(ctx context.Context, db accrepo.DBTX, id string) (*domain.Authorization, error) {
+	return scanAuthorization(db.QueryRowContext(ctx, lockAuthorizationID, id))
+}
+
+func updateAuthorization(ctx context.Context, db accrepo.DBTX, auth *domain.Authorization) error {
+	var transactionID sql.NullString
+	if auth.TransactionID != "" {
+		transactionID = sql.NullString{String: auth.TransactionID, Valid: true}
+	}
+	_, err := db.ExecContext(ctx, updateAuthorizationStatus,
+		auth.ID,
+		auth.Status,
+		auth.Captured.MinorUnits(),
+		transactionID,
+		auth.UpdatedAt,
+	)
+	return err
+}
+
+func scanAuthorization(row rowScanner) (*domain.Authorization, error) {
+	var (
+		i                domain.Authorization
+		amount, captured int64
+		currency         string
+		transactionID    sql.NullString
+	)
+	err := row.Scan(
+		&i.ID,
+		&i.CardID,
+		&i.AccountNumber,
+		&i.Merchant,
+		&amount,
+		&captured,
+		&currency,
+		&i.Status,
+		&transactionID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAuthorizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	i.Amount = domain.NewMoney(amount, currency)
+	i.Captured = domain.NewMoney(captured, currency)
+	i.TransactionID = transactionID.String
+	return &i, nil
+}
+
+func NewAuthorizationRepository(DB *sql.DB) AuthorizationRepository {
+	return &authorizationRepository{
+		logger: utils.NewLogger("AuthorizationRepository"),
+		db:     DB,
+		ledger: accrepo.NewTransactionRepository(DB),
+	}
+}
+
+const (
+	authorizationColumns      = `id, card_id, account_number, merchant, amount, captured, currency, status, transaction_id, expires_at, created_at, updated_at`
+	createAuthorization       = `INSERT INTO card_authorizations (id, card_id, account_number, merchant, amount, captured, currency, status, expires_at, created_at, updated_at) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	getAuthorizationID        = `SELECT ` + authorizationColumns + ` FROM card_authorizations WHERE id = $1`
+	lockAuthorizationID       = `SELECT ` + authorizationColumns + ` FROM card_authorizations WHERE id = $1 FOR UPDATE`
+	listExpiredAuthorizations = `SELECT id FROM card_authorizations WHERE status = 'pending' AND expires_at <= $1 ORDER BY expires_at LIMIT $2`
+	updateAuthorizationStatus = `UPDATE card_authorizations set status = $2, captured = $3, transaction_id = $4, updated_at = $5 WHERE id = $1`
+)
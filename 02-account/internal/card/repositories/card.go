@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type (
+	CardRepository interface {
+		CreateCard(ctx context.Context, card *domain.Card) error
+		GetCardID(ctx context.Context, id string) (*domain.Card, error)
+		GetCardToken(ctx context.Context, panToken string) (*domain.Card, error)
+		ListAccountCards(ctx context.Context, accountNumber string) ([]domain.Card, error)
+		UpdateStatus(ctx context.Context, card *domain.Card) error
+		CountAttempt(ctx context.Context, id string) (*domain.Card, error)
+		ResetAttempts(ctx context.Context, id string) error
+	}
+
+	cardRepository struct {
+		logger *utils.Logger
+		db     *sql.DB
+	}
+)
+
+// CreateCard implements CardRepository.
+func (cr *cardRepository) CreateCard(ctx context.Context, card *domain.Card) error {
+	_, err := cr.db.ExecContext(ctx, createCard,
+		card.ID,
+		card.AccountNumber,
+		card.CustomerID,
+		card.Brand,
+		card.PANToken,
+		card.MaskedPAN,
+		card.CCVHash,
+		card.ExpirationMonth,
+		card.ExpirationYear,
+		card.Status,
+		card.Attempts,
+		card.CreatedAt,
+		card.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetCardID implements CardRepository.
+func (cr *cardRepository) GetCardID(ctx context.Context, id string) (*domain.Card, error) {
+	return scanCard(cr.db.QueryRowContext(ctx, getCardID, id))
+}
+
+// GetCardToken implements CardRepository.
+func (cr *cardRepository) GetCardToken(ctx context.Context, panToken string) (*domain.Card, error) {
+	return scanCard(cr.db.QueryRowContext(ctx, getCardToken, panToken))
+}
+
+// ListAccountCards implements CardRepository.
+func (cr *cardRepository) ListAccountCards(ctx context.Context, accountNumber string) ([]domain.Card, error) {
+	rows, err := cr.db.QueryContext(ctx, listAccountCards, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.Card
+	for rows.Next() {
+		i, err := scanCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpdateStatus implements CardRepository.
+func (cr *cardRepository) UpdateStatus(ctx context.Context, card *domain.Card) error {
+	_, err := cr.db.ExecContext(ctx, updateCardStatus, card.ID, card.Status, card.Attempts, card.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CountAttempt implements CardRepository. It adds one verification attempt
+// in a single statement, so concurrent attempts each see their own count.
+func (cr *cardRepository) CountAttempt(ctx context.Context, id string) (*domain.Card, error) {
+	return scanCard(cr.db.QueryRowContext(ctx, countCardAttempt, id))
+}
+
+// ResetAttempts implements CardRepository.
+func (cr *cardRepository) ResetAttempts(ctx context.Context, id string) error {
+	_, err := cr.db.ExecContext(ctx, resetCardAttempts, id)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCard(row rowScanner) (*domain.Card, error) {
+	var i domain.Card
+	err := row.Scan(
+		&i.ID,
+		&i.AccountNumber,
+		&i.CustomerID,
+		&i.Brand,
+		&i.PANToken,
+		&i.MaskedPAN,
+		&i.CCVHash,
+		&i.ExpirationMonth,
+		&i.ExpirationYear,
+		&i.Status,
+		&i.Attempts,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func NewCardRepository(DB *sql.DB) CardRepository {
+	return &cardRepository{
+		logger: utils.NewLogger("CardRepository"),
+		db:     DB,
+	}
+}
+
+const (
+	cardColumns       = `id, account_number, customer_id, brand, pan_token, masked_pan, ccv_hash, expiration_month, expiration_year, status, attempts, created_at, updated_at`
+	createCard        = `INSERT INTO cards (` + cardColumns + `) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+	getCardID         = `SELECT ` + cardColumns + ` FROM cards WHERE id = $1`
+	getCardToken      = `SELECT ` + cardColumns + ` FROM cards WHERE pan_token = $1`
+	listAccountCards  = `SELECT ` + cardColumns + ` FROM cards WHERE account_number = $1 ORDER BY created_at`
+	updateCardStatus  = `UPDATE cards set status = $2, attempts = $3, updated_at = $4 WHERE id = $1`
+	countCardAttempt  = `UPDATE cards set attempts = attempts + 1 WHERE id = $1 RETURNING ` + cardColumns
+	resetCardAttempts = `UPDATE cards set attempts = 0 WHERE id = $1 AND attempts > 0`
+)
@@ -0,0 +1,205 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/card/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// expireBatch bounds how many abandoned authorizations one sweep releases.
+const expireBatch = 100
+
+type (
+	AuthorizationUseCase interface {
+		Authorize(ctx context.Context, req presenter.AuthorizeRequest) (*presenter.AuthorizationResponse, error)
+		Capture(ctx context.Context, req presenter.CaptureRequest) (*presenter.AuthorizationResponse, error)
+		Void(ctx context.Context, req presenter.AuthorizationIDRequest) (*presenter.AuthorizationResponse, error)
+		Find(ctx context.Context, req presenter.AuthorizationIDRequest) (*presenter.AuthorizationResponse, error)
+		ExpirePending(ctx context.Context) (int, error)
+	}
+
+	authorizationUseCase struct {
+		logger      *utils.Logger
+		cards       repositories.CardRepository
+		repo        repositories.AuthorizationRepository
+		audit       audit.Auditor
+		panKey      []byte
+		ttl         time.Duration
+		maxAttempts int
+	}
+)
+
+// Authorize implements AuthorizationUseCase.
+func (auc *authorizationUseCase) Authorize(ctx context.Context, req presenter.AuthorizeRequest) (*presenter.AuthorizationResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+	if !domain.ValidLuhn(req.PAN) {
+		return nil, domain.ErrInvalidPAN
+	}
+	amount, err := domain.ParseMoney(req.Amount.String(), req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	card, err := auc.cards.GetCardToken(ctx, domain.TokenizePAN(req.PAN, auc.panKey))
+	if errors.Is(err, domain.ErrCardNotFound) {
+		return nil, domain.ErrCardDeclined
+	}
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error getting card: %v", err)
+		return nil, err
+	}
+
+	if err := auc.verify(ctx, card, req); err != nil {
+		auc.audit.Record(ctx, audit.Event{
+			Action:     "card.authorize",
+			CustomerID: card.CustomerID,
+			Resource:   card.ID,
+			Outcome:    audit.OutcomeDenied,
+			Detail:     err.Error(),
+		})
+		return nil, err
+	}
+
+	auth := domain.NewAuthorization(card, req.Merchant, amount, auc.ttl)
+	if err := auc.repo.Authorize(ctx, auth); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error authorizing card %s: %v", card.ID, err)
+		return nil, err
+	}
+
+	res := presenter.NewAuthorizationResponse(auth)
+	return &res, nil
+}
+
+// verify checks the card details of req, allowing maxAttempts failed
+// verifications in a row before the card is blocked. The attempt is counted
+// before the CCV is compared, so concurrent guesses cannot all slip in while
+// the count is still low: those beyond the limit are declined unchecked.
+func (auc *authorizationUseCase) verify(ctx context.Context, card *domain.Card, req presenter.AuthorizeRequest) error {
+	if card.Status == domain.CardBlocked {
+		return domain.ErrCardBlocked
+	}
+	counted, err := auc.cards.CountAttempt(ctx, card.ID)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error counting attempt on card %s: %v", card.ID, err)
+		return err
+	}
+	if counted.Attempts > auc.maxAttempts {
+		return domain.ErrCardDeclined
+	}
+
+	verr := counted.Verify(req.CCV, req.ExpirationMonth, req.ExpirationYear, time.Now())
+	if verr == nil {
+		if err := auc.cards.ResetAttempts(ctx, card.ID); err != nil {
+			auc.logger.WithContext(ctx).Errorf("error resetting attempts on card %s: %v", card.ID, err)
+			return err
+		}
+		return nil
+	}
+	if counted.Attempts < auc.maxAttempts || errors.Is(verr, domain.ErrCardBlocked) {
+		return verr
+	}
+
+	counted.Block()
+	if err := auc.cards.UpdateStatus(ctx, counted); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error blocking card %s: %v", card.ID, err)
+		return err
+	}
+	return fmt.Errorf("%w after %d failed verifications", domain.ErrCardBlocked, counted.Attempts)
+}
+
+// Capture implements AuthorizationUseCase.
+func (auc *authorizationUseCase) Capture(ctx context.Context, req presenter.CaptureRequest) (*presenter.AuthorizationResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	var amount domain.Money
+	if req.Amount != "" {
+		current, err := auc.repo.GetAuthorizationID(ctx, req.AuthorizationID)
+		if err != nil {
+			return nil, err
+		}
+		amount, err = domain.ParseMoney(req.Amount.String(), current.Amount.Currency())
+		if err != nil {
+			return nil, err
+		}
+		if !amount.IsPositive() {
+			return nil, domain.ErrInvalidAmount
+		}
+	}
+
+	auth, err := auc.repo.Capture(ctx, req.AuthorizationID, amount)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error capturing authorization %s: %v", req.AuthorizationID, err)
+		return nil, err
+	}
+
+	res := presenter.NewAuthorizationResponse(auth)
+	return &res, nil
+}
+
+// Void implements AuthorizationUseCase.
+func (auc *authorizationUseCase) Void(ctx context.Context, req presenter.AuthorizationIDRequest) (*presenter.AuthorizationResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	auth, err := auc.repo.Void(ctx, req.AuthorizationID)
+	if err != nil {
+		auc.logger.WithContext(ctx).Errorf("error voiding authorization %s: %v", req.AuthorizationID, err)
+		return nil, err
+	}
+
+	res := presenter.NewAuthorizationResponse(auth)
+	return &res, nil
+}
+
+// Find implements AuthorizationUseCase.
+func (auc *authorizationUseCase) Find(ctx context.Context, req presenter.AuthorizationIDRequest) (*presenter.AuthorizationResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		auc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	auth, err := auc.repo.GetAuthorizationID(ctx, req.AuthorizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := presenter.NewAuthorizationResponse(auth)
+	return &res, nil
+}
+
+// ExpirePending implements AuthorizationUseCase.
+func (auc *authorizationUseCase) ExpirePending(ctx context.Context) (int, error) {
+	return auc.repo.ExpirePending(ctx, time.Now(), expireBatch)
+}
+
+// NewAuthorizationUseCase blocks a card after maxAttempts failed
+// verifications in a row.
+func NewAuthorizationUseCase(cards repositories.CardRepository, repo repositories.AuthorizationRepository, auditor audit.Auditor, panKey []byte, ttl time.Duration, maxAttempts int) AuthorizationUseCase {
+	return &authorizationUseCase{
+		logger:      utils.NewLogger("usecaseAuthorization"),
+		cards:       cards,
+		repo:        repo,
+		audit:       auditor,
+		panKey:      panKey,
+		ttl:         ttl,
+		maxAttempts: maxAttempts,
+	}
+}
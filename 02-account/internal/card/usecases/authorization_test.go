@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/card/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+const (
+	testPAN = "4111111111111111"
+	testCCV = "123"
+)
+
+var testPANKey = []byte("test-pan-key")
+
+// fakeCards keeps one card in memory, counting attempts the way the SQL
+// repository does: atomically.
+type fakeCards struct {
+	repositories.CardRepository
+	mu   sync.Mutex
+	card domain.Card
+}
+
+func (f *fakeCards) GetCardToken(ctx context.Context, panToken string) (*domain.Card, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if panToken != f.card.PANToken {
+		return nil, domain.ErrCardNotFound
+	}
+	c := f.card
+	return &c, nil
+}
+
+func (f *fakeCards) CountAttempt(ctx context.Context, id string) (*domain.Card, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.card.Attempts++
+	c := f.card
+	return &c, nil
+}
+
+func (f *fakeCards) ResetAttempts(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.card.Attempts = 0
+	return nil
+}
+
+func (f *fakeCards) UpdateStatus(ctx context.Context, card *domain.Card) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.card.Status = card.Status
+	return nil
+}
+
+type fakeAuthorizations struct {
+	repositories.AuthorizationRepository
+}
+
+func (fakeAuthorizations) Authorize(ctx context.Context, auth *domain.Authorization) error {
+	return nil
+}
+
+func newTestAuthorizationUseCase(t *testing.T, maxAttempts int) (AuthorizationUseCase, *fakeCards) {
+	t.Helper()
+	hash, err := utils.HashSecret(testCCV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := time.Now().AddDate(1, 0, 0)
+	cards := &fakeCards{card: domain.Card{
+		ID:              "card-1",
+		PANToken:        domain.TokenizePAN(testPAN, testPANKey),
+		CCVHash:         hash,
+		ExpirationMonth: int(exp.Month()),
+		ExpirationYear:  exp.Year(),
+		Status:          domain.CardActive,
+	}}
+	uc := NewAuthorizationUseCase(cards, fakeAuthorizations{}, audit.NewLogAuditor(), testPANKey, time.Hour, maxAttempts)
+	return uc, cards
+}
+
+func authorizeRequest(cards *fakeCards, ccv string) presenter.AuthorizeRequest {
+	return presenter.AuthorizeRequest{
+		PAN:             testPAN,
+		CCV:             ccv,
+		ExpirationMonth: cards.card.ExpirationMonth,
+		ExpirationYear:  cards.card.ExpirationYear,
+		Merchant:        "shop",
+		Amount:          "10.00",
+	}
+}
+
+func TestAuthorizeBlocksCardAfterFailedCCVs(t *testing.T) {
+	uc, cards := newTestAuthorizationUseCase(t, 3)
+	ctx := context.Background()
+
+	for i := 1; i <= 2; i++ {
+		if _, err := uc.Authorize(ctx, authorizeRequest(cards, "999")); !errors.Is(err, domain.ErrInvalidCVV) {
+			t.Fatalf("attempt %d: err = %v, want ErrInvalidCVV", i, err)
+		}
+	}
+	// A success in between starts the count over.
+	if _, err := uc.Authorize(ctx, authorizeRequest(cards, testCCV)); err != nil {
+		t.Fatalf("right CCV: %v", err)
+	}
+	for i := 1; i <= 2; i++ {
+		if _, err := uc.Authorize(ctx, authorizeRequest(cards, "999")); !errors.Is(err, domain.ErrInvalidCVV) {
+			t.Fatalf("attempt %d after success: err = %v, want ErrInvalidCVV", i, err)
+		}
+	}
+	if _, err := uc.Authorize(ctx, authorizeRequest(cards, "999")); !errors.Is(err, domain.ErrCardBlocked) {
+		t.Fatalf("third failure: err = %v, want ErrCardBlocked", err)
+	}
+	if cards.card.Status != domain.CardBlocked {
+		t.Fatalf("card status = %s, want blocked", cards.card.Status)
+	}
+	if _, err := uc.Authorize(ctx, authorizeRequest(cards, testCCV)); !errors.Is(err, domain.ErrCardBlocked) {
+		t.Fatalf("right CCV on a blocked card: err = %v, want ErrCardBlocked", err)
+	}
+}
+
+// TestAuthorizeBoundsConcurrentGuesses fires many wrong guesses at once: no
+// more than the allowed number may reach the CCV comparison.
+func TestAuthorizeBoundsConcurrentGuesses(t *testing.T) {
+	uc, cards := newTestAuthorizationUseCase(t, 3)
+	ctx := context.Background()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		checked int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := uc.Authorize(ctx, authorizeRequest(cards, "999"))
+			// Only a compared CCV fails as invalid or blocks the card;
+			// the rest are declined unchecked or find it blocked.
+			if errors.Is(err, domain.ErrInvalidCVV) || strings.HasSuffix(fmt.Sprint(err), "failed verifications") {
+				mu.Lock()
+				checked++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if cards.card.Status != domain.CardBlocked {
+		t.Fatalf("card status = %s, want blocked", cards.card.Status)
+	}
+	if checked > 3 {
+		t.Fatalf("%d guesses were checked, want at most 3", checked)
+	}
+}
@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+
+	accrepo "github.com/adilsonmenechini/golabbank/account/internal/account/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/card/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type (
+	Write interface {
+		Issue(ctx context.Context, req presenter.IssueCardRequest) (*presenter.IssueCardResponse, error)
+		Block(ctx context.Context, req presenter.CardIDRequest) (*presenter.CardResponse, error)
+		Unblock(ctx context.Context, req presenter.CardIDRequest) (*presenter.CardResponse, error)
+	}
+	Reader interface {
+		ListByAccount(ctx context.Context, req presenter.AccountNumberRequest) ([]presenter.CardResponse, error)
+	}
+
+	CardUseCase interface {
+		Write
+		Reader
+	}
+
+	cardUseCase struct {
+		logger   *utils.Logger
+		repo     repositories.CardRepository
+		accounts accrepo.AccountRepository
+		audit    audit.Auditor
+		panKey   []byte
+	}
+)
+
+// Issue implements CardUseCase.
+func (cuc *cardUseCase) Issue(ctx context.Context, req presenter.IssueCardRequest) (*presenter.IssueCardResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		cuc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	acc, err := cuc.ownedAccount(ctx, req.AccountNumber, "card.issue")
+	if err != nil {
+		return nil, err
+	}
+
+	card, issued, err := domain.NewCard(acc, req.Brand, cuc.panKey)
+	if err != nil {
+		cuc.logger.WithContext(ctx).Errorf("error generating card: %v", err)
+		return nil, err
+	}
+
+	if err := cuc.repo.CreateCard(ctx, card); err != nil {
+		cuc.logger.WithContext(ctx).Errorf("error creating card: %v", err)
+		return nil, err
+	}
+
+	return &presenter.IssueCardResponse{
+		CardResponse: presenter.NewCardResponse(card),
+		PAN:          issued.PAN,
+		CCV:          issued.CCV,
+	}, nil
+}
+
+// Block implements CardUseCase.
+func (cuc *cardUseCase) Block(ctx context.Context, req presenter.CardIDRequest) (*presenter.CardResponse, error) {
+	return cuc.setStatus(ctx, req, "card.block", (*domain.Card).Block)
+}
+
+// Unblock implements CardUseCase.
+func (cuc *cardUseCase) Unblock(ctx context.Context, req presenter.CardIDRequest) (*presenter.CardResponse, error) {
+	return cuc.setStatus(ctx, req, "card.unblock", (*domain.Card).Unblock)
+}
+
+// ListByAccount implements CardUseCase.
+func (cuc *cardUseCase) ListByAccount(ctx context.Context, req presenter.AccountNumberRequest) ([]presenter.CardResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		cuc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	if _, err := cuc.ownedAccount(ctx, req.AccountNumber, "card.list"); err != nil {
+		return nil, err
+	}
+
+	cards, err := cuc.repo.ListAccountCards(ctx, req.AccountNumber)
+	if err != nil {
+		cuc.logger.WithContext(ctx).Errorf("error listing cards: %v", err)
+		return nil, err
+	}
+
+	res := make([]presenter.CardResponse, 0, len(cards))
+	for i := range cards {
+		res = append(res, presenter.NewCardResponse(&cards[i]))
+	}
+	return res, nil
+}
+
+func (cuc *cardUseCase) setStatus(ctx context.Context, req presenter.CardIDRequest, action string, apply func(*domain.Card)) (*presenter.CardResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		cuc.logger.WithContext(ctx).Errorf("error validating request: %v", err)
+		return nil, err
+	}
+
+	card, err := cuc.repo.GetCardID(ctx, req.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cuc.ownedAccount(ctx, card.AccountNumber, action); err != nil {
+		return nil, err
+	}
+
+	apply(card)
+	if err := cuc.repo.UpdateStatus(ctx, card); err != nil {
+		cuc.logger.WithContext(ctx).Errorf("error updating card: %v", err)
+		return nil, err
+	}
+
+	res := presenter.NewCardResponse(card)
+	return &res, nil
+}
+
+// ownedAccount loads accountNumber and checks it belongs to the authenticated
+// customer, auditing any denial.
+func (cuc *cardUseCase) ownedAccount(ctx context.Context, accountNumber, action string) (*domain.Account, error) {
+	tk, ok := utils.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	acc, err := cuc.accounts.GetAccountNumber(ctx, accountNumber)
+	if err != nil {
+		cuc.logger.WithContext(ctx).Errorf("error getting account: %v", err)
+		return nil, err
+	}
+	if acc.CustomerID != tk.ID {
+		cuc.audit.Record(ctx, audit.Event{
+			Action:     action,
+			CustomerID: tk.ID,
+			Resource:   accountNumber,
+			Outcome:    audit.OutcomeDenied,
+			Detail:     domain.ErrAccountForbidden.Error(),
+		})
+		return nil, domain.ErrAccountForbidden
+	}
+	return acc, nil
+}
+
+func NewCardUseCase(repo repositories.CardRepository, accounts accrepo.AccountRepository, auditor audit.Auditor, panKey []byte) CardUseCase {
+	return &cardUseCase{
+		logger:   utils.NewLogger("usecaseCard"),
+		repo:     repo,
+		accounts: accounts,
+		audit:    auditor,
+		panKey:   panKey,
+	}
+}
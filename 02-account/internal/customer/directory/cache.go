@@ -0,0 +1,53 @@
+package directory
+
+import (
+	"sync"
+	"time"
+)
+
+// cache is a small map of entries that expire on their own deadline. When it
+// grows past max, expired entries are dropped and, if that is not enough, the
+// whole map is reset: a miss only costs a call to the customer service.
+type cache[V any] struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]cacheEntry[V]
+}
+
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func newCache[V any](max int) *cache[V] {
+	return &cache[V]{max: max, entries: make(map[string]cacheEntry[V])}
+}
+
+func (c *cache[V]) get(key string, now time.Time) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !now.Before(e.expires) {
+		delete(c.entries, key)
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (c *cache[V]) put(key string, value V, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.max {
+		now := time.Now()
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.max {
+			c.entries = make(map[string]cacheEntry[V])
+		}
+	}
+	c.entries[key] = cacheEntry[V]{value: value, expires: expires}
+}
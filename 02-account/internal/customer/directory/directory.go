@@ -0,0 +1,21 @@
+// Package directory resolves customer identity for the account service by
+// asking the customer service over HTTP. The customers table and the tokens
+// belong to that service.
+package directory
+
+import (
+	"context"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// CustomerDirectory is everything the account service needs to know about
+// customers. Returned customers never carry a password hash.
+type CustomerDirectory interface {
+	// Lookup returns the customer with id, or domain.ErrCustomerNotFound.
+	Lookup(ctx context.Context, id string) (domain.Customer, error)
+	// CheckToken returns domain.ErrTokenRevoked when the access token
+	// described by claims has been revoked.
+	CheckToken(ctx context.Context, claims utils.Claims) error
+}
@@ -0,0 +1,164 @@
+// Package directorytest provides an in-memory stand-in for the customer
+// service's directory routes, for exercising the HTTP CustomerDirectory
+// without a database.
+package directorytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/customer/directory"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+)
+
+const (
+	customerPath   = "/api/v1/customer/directory/customers/"
+	tokenCheckPath = "/api/v1/customer/directory/tokens/check"
+)
+
+// Server answers directory requests from the customers and revocations it
+// has been given. Requests without the right service key get 403.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	key       string
+	customers map[string]domain.Customer
+	revoked   map[string]bool
+	cutoffs   map[string]time.Time
+	failures  int
+	delay     time.Duration
+	calls     int
+}
+
+// NewServer starts a stand-in that expects serviceKey. Close it when done.
+func NewServer(serviceKey string, customers ...domain.Customer) *Server {
+	s := &Server{
+		key:       serviceKey,
+		customers: make(map[string]domain.Customer),
+		revoked:   make(map[string]bool),
+		cutoffs:   make(map[string]time.Time),
+	}
+	for _, cr := range customers {
+		s.customers[cr.ID] = cr
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Config returns an HTTPConfig pointing at the stand-in.
+func (s *Server) Config() directory.HTTPConfig {
+	return directory.HTTPConfig{BaseURL: s.URL, ServiceKey: s.key, Backoff: time.Millisecond}
+}
+
+// AddCustomer makes cr resolvable.
+func (s *Server) AddCustomer(cr domain.Customer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.customers[cr.ID] = cr
+}
+
+// RevokeToken revokes the access token with jti.
+func (s *Server) RevokeToken(jti string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[jti] = true
+}
+
+// RevokeCustomer revokes the customer's tokens issued before notBefore.
+func (s *Server) RevokeCustomer(customerID string, notBefore time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cutoffs[customerID] = notBefore
+}
+
+// FailNext makes the next n requests answer 503.
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+// Delay makes every request wait d before it is answered.
+func (s *Server) Delay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// Calls is the number of requests received so far.
+func (s *Server) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.calls++
+	delay := s.delay
+	s.mu.Unlock()
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Header.Get(directory.ServiceKeyHeader) != s.key {
+		reply(w, http.StatusForbidden, nil)
+		return
+	}
+	if s.failures > 0 {
+		s.failures--
+		reply(w, http.StatusServiceUnavailable, nil)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, customerPath):
+		cr, ok := s.customers[strings.TrimPrefix(r.URL.Path, customerPath)]
+		if !ok {
+			reply(w, http.StatusNotFound, nil)
+			return
+		}
+		reply(w, http.StatusOK, presenter.CustomerResponse{
+			ID:        cr.ID,
+			Name:      cr.Name,
+			Email:     cr.Email,
+			CreatedAt: cr.CreatedAt,
+		})
+	case r.Method == http.MethodPost && r.URL.Path == tokenCheckPath:
+		var req presenter.TokenCheckRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			reply(w, http.StatusBadRequest, nil)
+			return
+		}
+		cutoff, ok := s.cutoffs[req.CustomerID]
+		revoked := s.revoked[req.JTI] || (ok && time.UnixMicro(req.IssuedAtMicros).Before(cutoff))
+		reply(w, http.StatusOK, presenter.TokenCheckResponse{Revoked: revoked})
+	default:
+		reply(w, http.StatusNotFound, nil)
+	}
+}
+
+// reply writes the same envelope as the customer service's presenters.
+func reply(w http.ResponseWriter, statusCode int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	body := map[string]any{"statusCode": statusCode, "status": "success"}
+	if statusCode != http.StatusOK {
+		body["status"] = "error"
+	} else {
+		body["data"] = data
+	}
+	json.NewEncoder(w).Encode(body)
+}
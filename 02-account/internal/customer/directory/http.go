@@ -0,0 +1,234 @@
+package directory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+const (
+	// ServiceKeyHeader carries the shared secret the customer service expects
+	// on its directory routes.
+	ServiceKeyHeader = "X-Service-Key"
+
+	customerPath   = "/api/v1/customer/directory/customers/"
+	tokenCheckPath = "/api/v1/customer/directory/tokens/check"
+	cacheEntries   = 10000
+)
+
+// HTTPConfig tunes the client of the customer service. Zero values fall back
+// to the defaults noted on each field.
+type HTTPConfig struct {
+	BaseURL    string
+	ServiceKey string
+	// Timeout bounds each attempt (default 2s).
+	Timeout time.Duration
+	// Retries is how many times a failed attempt is retried (default 2, a
+	// negative value disables retries). Only network errors, 429 and 5xx
+	// responses are retried.
+	Retries int
+	// Backoff is the wait before the first retry, doubled after each one
+	// (default 100ms).
+	Backoff time.Duration
+	// CustomerTTL is how long a looked-up customer is cached (default 5m).
+	CustomerTTL time.Duration
+	// TokenTTL is how long a token is remembered as not revoked (default
+	// 15s), which bounds how late a logout is noticed here. Revoked tokens
+	// are remembered until they expire.
+	TokenTTL time.Duration
+}
+
+type httpDirectory struct {
+	logger    *utils.Logger
+	cfg       HTTPConfig
+	client    *http.Client
+	customers *cache[domain.Customer]
+	tokens    *cache[bool]
+}
+
+// NewHTTPDirectory returns a directory that asks the customer service at
+// cfg.BaseURL.
+func NewHTTPDirectory(cfg HTTPConfig) CustomerDirectory {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+	if cfg.Retries < 0 {
+		cfg.Retries = 0
+	} else if cfg.Retries == 0 {
+		cfg.Retries = 2
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 100 * time.Millisecond
+	}
+	if cfg.CustomerTTL <= 0 {
+		cfg.CustomerTTL = 5 * time.Minute
+	}
+	if cfg.TokenTTL <= 0 {
+		cfg.TokenTTL = 15 * time.Second
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &httpDirectory{
+		logger:    utils.NewLogger("customerDirectory"),
+		cfg:       cfg,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		customers: newCache[domain.Customer](cacheEntries),
+		tokens:    newCache[bool](cacheEntries),
+	}
+}
+
+// Lookup implements CustomerDirectory.
+func (hd *httpDirectory) Lookup(ctx context.Context, id string) (domain.Customer, error) {
+	if cr, ok := hd.customers.get(id, time.Now()); ok {
+		return cr, nil
+	}
+
+	var res presenter.CustomerResponse
+	if err := hd.call(ctx, http.MethodGet, customerPath+url.PathEscape(id), nil, &res); err != nil {
+		return domain.Customer{}, err
+	}
+	cr := domain.Customer{
+		ID:        res.ID,
+		Name:      res.Name,
+		Email:     res.Email,
+		CreatedAt: res.CreatedAt,
+	}
+	hd.customers.put(id, cr, time.Now().Add(hd.cfg.CustomerTTL))
+	return cr, nil
+}
+
+// CheckToken implements CustomerDirectory. Tokens without a jti or issue time
+// cannot be checked and are refused.
+func (hd *httpDirectory) CheckToken(ctx context.Context, claims utils.Claims) error {
+	jti := claims.RegisteredClaims.ID
+	if jti == "" || claims.IssuedAt == nil {
+		return domain.ErrTokenRevoked
+	}
+	now := time.Now()
+	if revoked, ok := hd.tokens.get(jti, now); ok {
+		if revoked {
+			return domain.ErrTokenRevoked
+		}
+		return nil
+	}
+
+	req := presenter.TokenCheckRequest{
+		JTI:            jti,
+		CustomerID:     claims.ID,
+		IssuedAtMicros: claims.IssuedAt.UnixMicro(),
+	}
+	var res presenter.TokenCheckResponse
+	if err := hd.call(ctx, http.MethodPost, tokenCheckPath, req, &res); err != nil {
+		return err
+	}
+
+	expires := now.Add(hd.cfg.TokenTTL)
+	if res.Revoked && claims.ExpiresAt != nil {
+		expires = claims.ExpiresAt.Time
+	}
+	hd.tokens.put(jti, res.Revoked, expires)
+	if res.Revoked {
+		return domain.ErrTokenRevoked
+	}
+	return nil
+}
+
+// errRetryable marks a failed attempt worth repeating.
+type errRetryable struct{ err error }
+
+func (e errRetryable) Error() string { return e.err.Error() }
+func (e errRetryable) Unwrap() error { return e.err }
+
+// call sends body as JSON and decodes the data field of the response
+// envelope into out, retrying transient failures with exponential backoff.
+func (hd *httpDirectory) call(ctx context.Context, method, path string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	wait := hd.cfg.Backoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = hd.do(ctx, method, path, payload, out)
+		var retry errRetryable
+		if err == nil || !errors.As(err, &retry) || attempt == hd.cfg.Retries {
+			break
+		}
+		hd.logger.WithContext(ctx).Errorf("customer service %s %s failed, retrying: %v", method, path, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v", domain.ErrDirectoryUnavailable, ctx.Err())
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	var retry errRetryable
+	if errors.As(err, &retry) {
+		return fmt.Errorf("%w: %v", domain.ErrDirectoryUnavailable, retry.err)
+	}
+	return err
+}
+
+func (hd *httpDirectory) do(ctx context.Context, method, path string, payload []byte, out any) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, hd.cfg.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(ServiceKeyHeader, hd.cfg.ServiceKey)
+	if id, ok := utils.RequestIDFromContext(ctx); ok {
+		req.Header.Set(utils.RequestIDHeader, id)
+	}
+	tracing.Inject(ctx, req.Header)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := hd.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: %v", domain.ErrDirectoryUnavailable, err)
+		}
+		return errRetryable{err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return domain.ErrCustomerNotFound
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return errRetryable{fmt.Errorf("status %d", resp.StatusCode)}
+	default:
+		return fmt.Errorf("%w: status %d", domain.ErrDirectoryUnavailable, resp.StatusCode)
+	}
+
+	envelope := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrDirectoryUnavailable, err)
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrDirectoryUnavailable, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package directory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/customer/directory"
+	"github.com/adilsonmenechini/golabbank/account/internal/customer/directory/directorytest"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const serviceKey = "service-key"
+
+var alice = domain.Customer{ID: "alice", Name: "Alice", Email: "alice@example.com"}
+
+func accessClaims(jti string, issued time.Time) utils.Claims {
+	return utils.Claims{
+		ID: alice.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(issued),
+			ExpiresAt: jwt.NewNumericDate(issued.Add(time.Hour)),
+		},
+	}
+}
+
+func TestLookupRetriesTransientFailures(t *testing.T) {
+	srv := directorytest.NewServer(serviceKey, alice)
+	defer srv.Close()
+	dir := directory.NewHTTPDirectory(srv.Config())
+
+	srv.FailNext(2)
+	cr, err := dir.Lookup(context.Background(), alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cr.Name != alice.Name || srv.Calls() != 3 {
+		t.Fatalf("got %+v after %d calls, want alice after 3", cr, srv.Calls())
+	}
+}
+
+func TestLookupGivesUpAfterRetries(t *testing.T) {
+	srv := directorytest.NewServer(serviceKey, alice)
+	defer srv.Close()
+	cfg := srv.Config()
+	cfg.Retries = 1
+	dir := directory.NewHTTPDirectory(cfg)
+
+	srv.FailNext(5)
+	if _, err := dir.Lookup(context.Background(), alice.ID); !errors.Is(err, domain.ErrDirectoryUnavailable) {
+		t.Fatalf("err = %v, want ErrDirectoryUnavailable", err)
+	}
+	if srv.Calls() != 2 {
+		t.Fatalf("%d calls, want the attempt and one retry", srv.Calls())
+	}
+}
+
+// TestLookupDoesNotRetryFinalAnswers covers answers that another attempt
+// would not change: an unknown customer and a refused service key.
+func TestLookupDoesNotRetryFinalAnswers(t *testing.T) {
+	srv := directorytest.NewServer(serviceKey, alice)
+	defer srv.Close()
+
+	if _, err := directory.NewHTTPDirectory(srv.Config()).Lookup(context.Background(), "bob"); !errors.Is(err, domain.ErrCustomerNotFound) {
+		t.Fatalf("unknown customer: err = %v, want ErrCustomerNotFound", err)
+	}
+	cfg := srv.Config()
+	cfg.ServiceKey = "wrong"
+	if _, err := directory.NewHTTPDirectory(cfg).Lookup(context.Background(), alice.ID); !errors.Is(err, domain.ErrDirectoryUnavailable) {
+		t.Fatalf("wrong service key: err = %v, want ErrDirectoryUnavailable", err)
+	}
+	if srv.Calls() != 2 {
+		t.Fatalf("%d calls, want one per lookup", srv.Calls())
+	}
+}
+
+func TestLookupCachesCustomers(t *testing.T) {
+	srv := directorytest.NewServer(serviceKey, alice)
+	defer srv.Close()
+	cfg := srv.Config()
+	cfg.CustomerTTL = 50 * time.Millisecond
+	dir := directory.NewHTTPDirectory(cfg)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := dir.Lookup(ctx, alice.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if srv.Calls() != 1 {
+		t.Fatalf("%d calls for three lookups, want 1", srv.Calls())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := dir.Lookup(ctx, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Calls() != 2 {
+		t.Fatalf("%d calls, want the expired entry fetched again", srv.Calls())
+	}
+}
+
+func TestLookupTimesOut(t *testing.T) {
+	srv := directorytest.NewServer(serviceKey, alice)
+	defer srv.Close()
+	srv.Delay(time.Second)
+	cfg := srv.Config()
+	cfg.Timeout = 20 * time.Millisecond
+	cfg.Retries = 1
+	dir := directory.NewHTTPDirectory(cfg)
+
+	start := time.Now()
+	_, err := dir.Lookup(context.Background(), alice.ID)
+	if !errors.Is(err, domain.ErrDirectoryUnavailable) {
+		t.Fatalf("err = %v, want ErrDirectoryUnavailable", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("lookup took %s, the timeout is not applied", elapsed)
+	}
+	if srv.Calls() != 2 {
+		t.Fatalf("%d calls, want a timed-out attempt to be retried once", srv.Calls())
+	}
+}
+
+// TestCheckTokenCache remembers a valid token for TokenTTL, so a logout is
+// noticed late by at most that long, and a revoked one for good.
+func TestCheckTokenCache(t *testing.T) {
+	srv := directorytest.NewServer(serviceKey, alice)
+	defer srv.Close()
+	cfg := srv.Config()
+	cfg.TokenTTL = 50 * time.Millisecond
+	dir := directory.NewHTTPDirectory(cfg)
+	ctx := context.Background()
+	claims := accessClaims("jti-1", time.Now())
+
+	if err := dir.CheckToken(ctx, claims); err != nil {
+		t.Fatal(err)
+	}
+	srv.RevokeToken("jti-1")
+	if err := dir.CheckToken(ctx, claims); err != nil {
+		t.Fatalf("within TokenTTL: err = %v, want the cached answer", err)
+	}
+	if srv.Calls() != 1 {
+		t.Fatalf("%d calls, want 1", srv.Calls())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if err := dir.CheckToken(ctx, claims); !errors.Is(err, domain.ErrTokenRevoked) {
+			t.Fatalf("after TokenTTL: err = %v, want ErrTokenRevoked", err)
+		}
+	}
+	if srv.Calls() != 2 {
+		t.Fatalf("%d calls, want the revocation cached", srv.Calls())
+	}
+}
+
+func TestCheckTokenRefusesUncheckableTokens(t *testing.T) {
+	srv := directorytest.NewServer(serviceKey, alice)
+	defer srv.Close()
+	dir := directory.NewHTTPDirectory(srv.Config())
+
+	claims := accessClaims("", time.Now())
+	if err := dir.CheckToken(context.Background(), claims); !errors.Is(err, domain.ErrTokenRevoked) {
+		t.Fatalf("token without jti: err = %v, want ErrTokenRevoked", err)
+	}
+	if srv.Calls() != 0 {
+		t.Fatalf("%d calls, want none", srv.Calls())
+	}
+}
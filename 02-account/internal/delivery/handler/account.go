@@ -1,19 +1,25 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
-
-	"github.com/adilsonmenechini/golabbank/internal/account/usecases"
-	"github.com/adilsonmenechini/golabbank/internal/delivery/presenter"
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"strconv"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/account/usecases"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/account/internal/statement"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/gorilla/mux"
 )
 
 type (
 	accountHandler struct {
 		logger *utils.Logger
 		pa     *presenter.AccountPresenter
-		rs     *presenter.ResponsePresenter
+		rs     *response.Presenter
 		us     usecases.AccountUseCase
 	}
 	AccountHandler interface {
@@ -23,6 +29,22 @@ type (
 		CreateAccountHandler(w http.ResponseWriter, r *http.Request)
 		PaymentHandler(w http.ResponseWriter, r *http.Request)
 		PaymentLimitHandler(w http.ResponseWriter, r *http.Request)
+		GetAccountHandler(w http.ResponseWriter, r *http.Request)
+		ListAccountsHandler(w http.ResponseWriter, r *http.Request)
+		StatementHandler(w http.ResponseWriter, r *http.Request)
+		ExportStatementHandler(w http.ResponseWriter, r *http.Request)
+		ReverseTransactionHandler(w http.ResponseWriter, r *http.Request)
+		RefundTransactionHandler(w http.ResponseWriter, r *http.Request)
+		ListProductsHandler(w http.ResponseWriter, r *http.Request)
+		CloseOwnAccountHandler(w http.ResponseWriter, r *http.Request)
+		ActivateAccountHandler(w http.ResponseWriter, r *http.Request)
+		FreezeAccountHandler(w http.ResponseWriter, r *http.Request)
+		UnfreezeAccountHandler(w http.ResponseWriter, r *http.Request)
+		CloseAccountHandler(w http.ResponseWriter, r *http.Request)
+		InspectAccountHandler(w http.ResponseWriter, r *http.Request)
+		AdjustLimitHandler(w http.ResponseWriter, r *http.Request)
+		CashDepositHandler(w http.ResponseWriter, r *http.Request)
+		CashWithdrawHandler(w http.ResponseWriter, r *http.Request)
 	}
 )
 
@@ -30,24 +52,24 @@ type (
 func (hac *accountHandler) CreateAccountHandler(w http.ResponseWriter, r *http.Request) {
 	tk, err := utils.GetTokenAuthorization(r)
 	if err != nil {
-		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, err.Error())
+		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
-	req := presenter.CreateAccountRequest{
-		CustomerID: tk.ID,
-		Name:       tk.Name,
-	}
-
+	var req presenter.CreateAccountRequest
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		hac.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 		return
 	}
+	req.CustomerID = tk.ID
+	if req.Name == "" {
+		req.Name = tk.Name
+	}
 
 	err = hac.us.Create(r.Context(), req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		respondError(hac.rs, w, err)
 		return
 	}
 
@@ -58,7 +80,7 @@ func (hac *accountHandler) CreateAccountHandler(w http.ResponseWriter, r *http.R
 func (hac *accountHandler) DepositHandler(w http.ResponseWriter, r *http.Request) {
 	_, err := utils.GetTokenAuthorization(r)
 	if err != nil {
-		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, err.Error())
+		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -66,13 +88,13 @@ func (hac *accountHandler) DepositHandler(w http.ResponseWriter, r *http.Request
 
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		hac.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 		return
 	}
 
 	err = hac.us.Deposit(r.Context(), req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		respondError(hac.rs, w, err)
 		return
 	}
 
@@ -83,7 +105,7 @@ func (hac *accountHandler) DepositHandler(w http.ResponseWriter, r *http.Request
 func (hac *accountHandler) PaymentHandler(w http.ResponseWriter, r *http.Request) {
 	_, err := utils.GetTokenAuthorization(r)
 	if err != nil {
-		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, err.Error())
+		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -91,13 +113,13 @@ func (hac *accountHandler) PaymentHandler(w http.ResponseWriter, r *http.Request
 
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		hac.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 		return
 	}
 
 	err = hac.us.Payment(r.Context(), req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		respondError(hac.rs, w, err)
 		return
 	}
 
@@ -108,7 +130,7 @@ func (hac *accountHandler) PaymentHandler(w http.ResponseWriter, r *http.Request
 func (hac *accountHandler) PaymentLimitHandler(w http.ResponseWriter, r *http.Request) {
 	_, err := utils.GetTokenAuthorization(r)
 	if err != nil {
-		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, err.Error())
+		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -116,13 +138,13 @@ func (hac *accountHandler) PaymentLimitHandler(w http.ResponseWriter, r *http.Re
 
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		hac.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 		return
 	}
 
 	err = hac.us.PaymentLimit(r.Context(), req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		respondError(hac.rs, w, err)
 		return
 	}
 
@@ -133,7 +155,7 @@ func (hac *accountHandler) PaymentLimitHandler(w http.ResponseWriter, r *http.Re
 func (hac *accountHandler) TransferHandler(w http.ResponseWriter, r *http.Request) {
 	_, err := utils.GetTokenAuthorization(r)
 	if err != nil {
-		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, err.Error())
+		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -141,13 +163,13 @@ func (hac *accountHandler) TransferHandler(w http.ResponseWriter, r *http.Reques
 
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		hac.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 		return
 	}
 
 	err = hac.us.Transfer(r.Context(), req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		respondError(hac.rs, w, err)
 		return
 	}
 
@@ -158,7 +180,7 @@ func (hac *accountHandler) TransferHandler(w http.ResponseWriter, r *http.Reques
 func (hac *accountHandler) WithdrawHandler(w http.ResponseWriter, r *http.Request) {
 	_, err := utils.GetTokenAuthorization(r)
 	if err != nil {
-		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, err.Error())
+		hac.rs.ResponseErrorToken(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -166,24 +188,232 @@ func (hac *accountHandler) WithdrawHandler(w http.ResponseWriter, r *http.Reques
 
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		hac.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
 		return
 	}
 
 	err = hac.us.Withdraw(r.Context(), req)
 	if err != nil {
-		hac.rs.ResponseError(w, http.StatusBadRequest, err.Error())
+		respondError(hac.rs, w, err)
 		return
 	}
 
 	hac.rs.ResponseSuccess(w, http.StatusOK, "Withdraw successfully")
 }
 
+// GetAccountHandler implements AccountHandler.
+func (hac *accountHandler) GetAccountHandler(w http.ResponseWriter, r *http.Request) {
+	req := presenter.AccountNumberRequest{
+		AccountNumber: mux.Vars(r)["account_number"],
+	}
+
+	res, err := hac.us.FindByAcoount(r.Context(), req)
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// ListAccountsHandler implements AccountHandler.
+func (hac *accountHandler) ListAccountsHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := hac.us.ListByCustomer(r.Context())
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// ListProductsHandler implements AccountHandler.
+func (hac *accountHandler) ListProductsHandler(w http.ResponseWriter, r *http.Request) {
+	hac.rs.ResponseData(w, http.StatusOK, hac.us.Products(r.Context()))
+}
+
+// StatementHandler implements AccountHandler.
+func (hac *accountHandler) StatementHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := presenter.StatementRequest{
+		AccountNumber: mux.Vars(r)["account_number"],
+		Cursor:        q.Get("cursor"),
+		From:          q.Get("from"),
+		To:            q.Get("to"),
+		Types:         q.Get("type"),
+	}
+	if l := q.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil {
+			hac.rs.ResponseError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		req.Limit = limit
+	}
+
+	res, err := hac.us.Statement(r.Context(), req)
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// ExportStatementHandler implements AccountHandler.
+func (hac *accountHandler) ExportStatementHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := presenter.ExportStatementRequest{
+		AccountNumber: mux.Vars(r)["account_number"],
+		Format:        q.Get("format"),
+		From:          q.Get("from"),
+		To:            q.Get("to"),
+	}
+	if req.Format == "" {
+		req.Format = string(statement.CSV)
+	}
+
+	format, err := statement.ParseFormat(req.Format)
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	out := &attachmentWriter{
+		w:           w,
+		rc:          http.NewResponseController(w),
+		contentType: format.ContentType(),
+		filename:    "statement-" + req.AccountNumber + "." + string(format),
+	}
+	err = hac.us.Export(r.Context(), req, out)
+	if err != nil {
+		if out.started {
+			// Headers are gone; all we can do is cut the stream short.
+			hac.logger.WithContext(r.Context()).Errorf("error streaming statement: %v", err)
+			return
+		}
+		respondError(hac.rs, w, err)
+		return
+	}
+}
+
+// ReverseTransactionHandler implements AccountHandler.
+func (hac *accountHandler) ReverseTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	req := presenter.ReversalRequest{
+		TransactionID: mux.Vars(r)["transaction_id"],
+	}
+
+	res, err := hac.us.Reverse(r.Context(), req)
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseData(w, http.StatusCreated, res)
+}
+
+// RefundTransactionHandler implements AccountHandler.
+func (hac *accountHandler) RefundTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	var req = presenter.RefundRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hac.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
+	req.TransactionID = mux.Vars(r)["transaction_id"]
+
+	res, err := hac.us.Refund(r.Context(), req)
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseData(w, http.StatusCreated, res)
+}
+
+// CloseOwnAccountHandler implements AccountHandler.
+func (hac *accountHandler) CloseOwnAccountHandler(w http.ResponseWriter, r *http.Request) {
+	req := presenter.AccountNumberRequest{
+		AccountNumber: mux.Vars(r)["account_number"],
+	}
+
+	err := hac.us.Delete(r.Context(), req)
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseSuccess(w, http.StatusOK, "Account closed successfully")
+}
+
+// ActivateAccountHandler implements AccountHandler.
+func (hac *accountHandler) ActivateAccountHandler(w http.ResponseWriter, r *http.Request) {
+	hac.changeStatus(w, r, hac.us.ActivateAccount)
+}
+
+// FreezeAccountHandler implements AccountHandler.
+func (hac *accountHandler) FreezeAccountHandler(w http.ResponseWriter, r *http.Request) {
+	hac.changeStatus(w, r, hac.us.FreezeAccount)
+}
+
+// UnfreezeAccountHandler implements AccountHandler.
+func (hac *accountHandler) UnfreezeAccountHandler(w http.ResponseWriter, r *http.Request) {
+	hac.changeStatus(w, r, hac.us.UnfreezeAccount)
+}
+
+// CloseAccountHandler implements AccountHandler.
+func (hac *accountHandler) CloseAccountHandler(w http.ResponseWriter, r *http.Request) {
+	hac.changeStatus(w, r, hac.us.CloseAccount)
+}
+
+func (hac *accountHandler) changeStatus(w http.ResponseWriter, r *http.Request, change func(context.Context, presenter.AccountNumberRequest) (*presenter.AccountResponse, error)) {
+	req := presenter.AccountNumberRequest{
+		AccountNumber: mux.Vars(r)["account_number"],
+	}
+
+	res, err := change(r.Context(), req)
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// exportWriteTimeout bounds each write of a streamed statement. It replaces
+// the server's WriteTimeout, which covers the whole response and would cut
+// off a long history: the export may take as long as it needs while it keeps
+// making progress.
+const exportWriteTimeout = 30 * time.Second
+
+// attachmentWriter sets download headers on the first write, so an error
+// raised before any output can still be reported as JSON.
+type attachmentWriter struct {
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (aw *attachmentWriter) Write(b []byte) (int, error) {
+	// Not every ResponseWriter supports deadlines; those keep the server's.
+	_ = aw.rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+	if !aw.started {
+		aw.started = true
+		aw.w.Header().Set("Content-Type", aw.contentType)
+		aw.w.Header().Set("Content-Disposition", `attachment; filename="`+aw.filename+`"`)
+		aw.w.WriteHeader(http.StatusOK)
+	}
+	return aw.w.Write(b)
+}
+
 func NewAccountHandler(usa usecases.AccountUseCase) AccountHandler {
 	return &accountHandler{
 		logger: utils.NewLogger("AccountHandler"),
 		us:     usa,
-		rs:     presenter.NewResponsePresenter(),
+		rs:     response.NewPresenter(),
 		pa:     presenter.NewAccountPresenter(),
 	}
 }
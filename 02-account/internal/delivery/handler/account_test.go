@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/account/usecases"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// fakeAccountUseCase records the request Create was called with. Methods the
+// test does not need panic through the nil embedded interface.
+type fakeAccountUseCase struct {
+	usecases.AccountUseCase
+	created presenter.CreateAccountRequest
+}
+
+// Export streams ten lines, pausing between them like a large ledger read
+// page by page.
+func (f *fakeAccountUseCase) Export(ctx context.Context, req presenter.ExportStatementRequest, w io.Writer) error {
+	for i := 0; i < 10; i++ {
+		if _, err := fmt.Fprintf(w, "line %d\n", i); err != nil {
+			return err
+		}
+		time.Sleep(30 * time.Millisecond)
+	}
+	return nil
+}
+
+func (f *fakeAccountUseCase) Create(ctx context.Context, req presenter.CreateAccountRequest) error {
+	f.created = req
+	return nil
+}
+
+func TestCreateAccountOwnerComesFromToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	token, err := utils.GenerateJWT("alice", "Alice", "alice@example.com", "customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	us := &fakeAccountUseCase{}
+	body := `{"customer_id":"mallory","account_type":"checking"}`
+	req := httptest.NewRequest(http.MethodPost, "/account", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	NewAccountHandler(us).CreateAccountHandler(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if us.created.CustomerID != "alice" {
+		t.Fatalf("account opened for %q, want the token's customer", us.created.CustomerID)
+	}
+	if us.created.Name != "Alice" {
+		t.Fatalf("name = %q, want the token's name", us.created.Name)
+	}
+}
+
+// TestExportOutlivesServerWriteTimeout streams a statement for longer than
+// the server's WriteTimeout: it must arrive whole.
+func TestExportOutlivesServerWriteTimeout(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(NewAccountHandler(&fakeAccountUseCase{}).ExportStatementHandler))
+	ts.Config.WriteTimeout = 100 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "?format=csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading the export: %v", err)
+	}
+	if want := "line 9\n"; !strings.HasSuffix(string(body), want) {
+		t.Fatalf("export cut short: %q", body)
+	}
+}
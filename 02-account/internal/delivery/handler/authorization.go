@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/card/usecases"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/gorilla/mux"
+)
+
+type (
+	authorizationHandler struct {
+		logger *utils.Logger
+		rs     *response.Presenter
+		us     usecases.AuthorizationUseCase
+	}
+	AuthorizationHandler interface {
+		AuthorizeHandler(w http.ResponseWriter, r *http.Request)
+		CaptureHandler(w http.ResponseWriter, r *http.Request)
+		VoidHandler(w http.ResponseWriter, r *http.Request)
+		GetAuthorizationHandler(w http.ResponseWriter, r *http.Request)
+	}
+)
+
+// AuthorizeHandler implements AuthorizationHandler.
+func (hau *authorizationHandler) AuthorizeHandler(w http.ResponseWriter, r *http.Request) {
+	var req = presenter.AuthorizeRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hau.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
+
+	res, err := hau.us.Authorize(r.Context(), req)
+	if err != nil {
+		respondError(hau.rs, w, err)
+		return
+	}
+
+	hau.rs.ResponseData(w, http.StatusCreated, res)
+}
+
+// CaptureHandler implements AuthorizationHandler.
+func (hau *authorizationHandler) CaptureHandler(w http.ResponseWriter, r *http.Request) {
+	var req = presenter.CaptureRequest{}
+
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			hau.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+			return
+		}
+	}
+	req.AuthorizationID = mux.Vars(r)["authorization_id"]
+
+	res, err := hau.us.Capture(r.Context(), req)
+	if err != nil {
+		respondError(hau.rs, w, err)
+		return
+	}
+
+	hau.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// VoidHandler implements AuthorizationHandler.
+func (hau *authorizationHandler) VoidHandler(w http.ResponseWriter, r *http.Request) {
+	req := presenter.AuthorizationIDRequest{
+		AuthorizationID: mux.Vars(r)["authorization_id"],
+	}
+
+	res, err := hau.us.Void(r.Context(), req)
+	if err != nil {
+		respondError(hau.rs, w, err)
+		return
+	}
+
+	hau.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// GetAuthorizationHandler implements AuthorizationHandler.
+func (hau *authorizationHandler) GetAuthorizationHandler(w http.ResponseWriter, r *http.Request) {
+	req := presenter.AuthorizationIDRequest{
+		AuthorizationID: mux.Vars(r)["authorization_id"],
+	}
+
+	res, err := hau.us.Find(r.Context(), req)
+	if err != nil {
+		respondError(hau.rs, w, err)
+		return
+	}
+
+	hau.rs.ResponseData(w, http.StatusOK, res)
+}
+
+func NewAuthorizationHandler(usc usecases.AuthorizationUseCase) AuthorizationHandler {
+	return &authorizationHandler{
+		logger: utils.NewLogger("AuthorizationHandler"),
+		us:     usc,
+		rs:     response.NewPresenter(),
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/gorilla/mux"
+)
+
+// InspectAccountHandler implements AccountHandler.
+func (hac *accountHandler) InspectAccountHandler(w http.ResponseWriter, r *http.Request) {
+	req := presenter.AccountNumberRequest{
+		AccountNumber: mux.Vars(r)["account_number"],
+	}
+
+	res, err := hac.us.InspectAccount(r.Context(), req)
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// AdjustLimitHandler implements AccountHandler.
+func (hac *accountHandler) AdjustLimitHandler(w http.ResponseWriter, r *http.Request) {
+	var req = presenter.AdjustLimitRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hac.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
+	req.AccountNumber = mux.Vars(r)["account_number"]
+
+	res, err := hac.us.AdjustLimit(r.Context(), req)
+	if err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// CashDepositHandler implements AccountHandler.
+func (hac *accountHandler) CashDepositHandler(w http.ResponseWriter, r *http.Request) {
+	hac.cash(w, r, hac.us.CashDeposit, "Deposit successfully")
+}
+
+// CashWithdrawHandler implements AccountHandler.
+func (hac *accountHandler) CashWithdrawHandler(w http.ResponseWriter, r *http.Request) {
+	hac.cash(w, r, hac.us.CashWithdraw, "Withdraw successfully")
+}
+
+func (hac *accountHandler) cash(w http.ResponseWriter, r *http.Request, post func(context.Context, presenter.OrderAccountRequest) error, done string) {
+	var req = presenter.OrderAccountRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hac.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
+	req.AccountNumber = mux.Vars(r)["account_number"]
+
+	if err := post(r.Context(), req); err != nil {
+		respondError(hac.rs, w, err)
+		return
+	}
+
+	hac.rs.ResponseSuccess(w, http.StatusOK, done)
+}
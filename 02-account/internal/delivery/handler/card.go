@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/card/usecases"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/presenter"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/gorilla/mux"
+)
+
+type (
+	cardHandler struct {
+		logger *utils.Logger
+		rs     *response.Presenter
+		us     usecases.CardUseCase
+	}
+	CardHandler interface {
+		IssueCardHandler(w http.ResponseWriter, r *http.Request)
+		ListCardsHandler(w http.ResponseWriter, r *http.Request)
+		BlockCardHandler(w http.ResponseWriter, r *http.Request)
+		UnblockCardHandler(w http.ResponseWriter, r *http.Request)
+	}
+)
+
+// IssueCardHandler implements CardHandler.
+func (hcd *cardHandler) IssueCardHandler(w http.ResponseWriter, r *http.Request) {
+	var req = presenter.IssueCardRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		hcd.rs.ResponseError(w, http.StatusBadRequest, invalidBody)
+		return
+	}
+
+	res, err := hcd.us.Issue(r.Context(), req)
+	if err != nil {
+		respondError(hcd.rs, w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	hcd.rs.ResponseData(w, http.StatusCreated, res)
+}
+
+// ListCardsHandler implements CardHandler.
+func (hcd *cardHandler) ListCardsHandler(w http.ResponseWriter, r *http.Request) {
+	req := presenter.AccountNumberRequest{
+		AccountNumber: r.URL.Query().Get("account_number"),
+	}
+
+	res, err := hcd.us.ListByAccount(r.Context(), req)
+	if err != nil {
+		respondError(hcd.rs, w, err)
+		return
+	}
+
+	hcd.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// BlockCardHandler implements CardHandler.
+func (hcd *cardHandler) BlockCardHandler(w http.ResponseWriter, r *http.Request) {
+	req := presenter.CardIDRequest{
+		CardID: mux.Vars(r)["card_id"],
+	}
+
+	res, err := hcd.us.Block(r.Context(), req)
+	if err != nil {
+		respondError(hcd.rs, w, err)
+		return
+	}
+
+	hcd.rs.ResponseData(w, http.StatusOK, res)
+}
+
+// UnblockCardHandler implements CardHandler.
+func (hcd *cardHandler) UnblockCardHandler(w http.ResponseWriter, r *http.Request) {
+	req := presenter.CardIDRequest{
+		CardID: mux.Vars(r)["card_id"],
+	}
+
+	res, err := hcd.us.Unblock(r.Context(), req)
+	if err != nil {
+		respondError(hcd.rs, w, err)
+		return
+	}
+
+	hcd.rs.ResponseData(w, http.StatusOK, res)
+}
+
+func NewCardHandler(usc usecases.CardUseCase) CardHandler {
+	return &cardHandler{
+		logger: utils.NewLogger("CardHandler"),
+		us:     usc,
+		rs:     response.NewPresenter(),
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/account/usecases"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/account/internal/statement"
+	"github.com/adilsonmenechini/golabbank/account/pkg/genrand"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/asaskevich/govalidator"
+)
+
+// invalidBody answers a request whose JSON body could not be decoded.
+const invalidBody = "invalid request body"
+
+// statusFor maps a usecase error to the HTTP status returned to the client.
+// Anything not listed here is an unexpected failure, such as a lost database
+// connection, and is reported as 500 so it is never replayed as final.
+func statusFor(err error) int {
+	var verr govalidator.Errors
+	switch {
+	case errors.As(err, &verr),
+		errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrMoneyOverflow),
+		errors.Is(err, domain.ErrInvalidCurrency),
+		errors.Is(err, domain.ErrCurrencyMismatch),
+		errors.Is(err, domain.ErrInvalidCardBrand),
+		errors.Is(err, usecases.ErrInvalidCursor),
+		errors.Is(err, usecases.ErrInvalidDate),
+		errors.Is(err, usecases.ErrInvalidType),
+		errors.Is(err, statement.ErrUnknownFormat):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUnauthenticated),
+		errors.Is(err, domain.ErrTokenRevoked):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrAccountForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, sql.ErrNoRows),
+		errors.Is(err, domain.ErrTransactionNotFound),
+		errors.Is(err, domain.ErrCustomerNotFound),
+		errors.Is(err, domain.ErrCardNotFound),
+		errors.Is(err, domain.ErrAuthorizationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTransactionReversed),
+		errors.Is(err, domain.ErrTransactionRefunded),
+		errors.Is(err, domain.ErrAuthorizationNotPending),
+		errors.Is(err, domain.ErrAccountPending),
+		errors.Is(err, domain.ErrAccountFrozen),
+		errors.Is(err, domain.ErrAccountClosed),
+		errors.Is(err, domain.ErrInvalidTransition):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInsufficientFunds),
+		errors.Is(err, domain.ErrDebitInsufficient),
+		errors.Is(err, domain.ErrPaymentInsufficient),
+		errors.Is(err, domain.ErrWithdrawalInsufficient),
+		errors.Is(err, domain.ErrTransferInsufficient),
+		errors.Is(err, domain.ErrRefundInsufficient),
+		errors.Is(err, domain.ErrDepositLimitExceeded),
+		errors.Is(err, domain.ErrPaymentLimitExceeded),
+		errors.Is(err, domain.ErrTransferSameAccount),
+		errors.Is(err, domain.ErrTransactionNotReversible),
+		errors.Is(err, domain.ErrTransactionNotRefundable),
+		errors.Is(err, domain.ErrCaptureExceedsHold),
+		errors.Is(err, domain.ErrUnknownProduct),
+		errors.Is(err, domain.ErrCurrencyNotOffered),
+		errors.Is(err, domain.ErrLimitAboveProductMax),
+		errors.Is(err, domain.ErrLimitBelowUsed),
+		errors.Is(err, domain.ErrAccountNotSettled):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrInvalidPAN),
+		errors.Is(err, domain.ErrInvalidCVV),
+		errors.Is(err, domain.ErrCreditCardExpired),
+		errors.Is(err, domain.ErrCreditLimitExceeded),
+		errors.Is(err, domain.ErrCardBlocked),
+		errors.Is(err, domain.ErrCardDeclined):
+		return http.StatusPaymentRequired
+	case errors.Is(err, genrand.ErrAllocationExhausted),
+		errors.Is(err, domain.ErrDirectoryUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondError writes err with the status from statusFor. Server-side
+// failures only report their status text: their messages describe internals,
+// not the request.
+func respondError(rs *response.Presenter, w http.ResponseWriter, err error) {
+	status := statusFor(err)
+	if status >= http.StatusInternalServerError {
+		rs.ResponseError(w, status, http.StatusText(status))
+		return
+	}
+	rs.ResponseError(w, status, err.Error())
+}
@@ -1,16 +1,21 @@
 package presenter
 
 import (
+	"encoding/json"
 	"time"
 
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
 )
 
+// CreateAccountRequest opens an account for the authenticated customer.
+// CustomerID always comes from the token, never from the body.
 type CreateAccountRequest struct {
-	CustomerID  string  `json:"customer_id" valid:"notnull"`
-	Name        string  `json:"name" valid:"notnull"`
-	AccountType string  `json:"account_type" valid:"notnull"`
-	Limit       float64 `json:"limit" valid:"notnull"`
+	CustomerID  string      `json:"-" valid:"notnull"`
+	Name        string      `json:"name" valid:"notnull"`
+	AccountType string      `json:"account_type" valid:"notnull"`
+	Limit       json.Number `json:"limit" valid:"optional"`
+	Currency    string      `json:"currency" valid:"optional"`
 }
 
 type AccountNumberRequest struct {
@@ -22,32 +27,56 @@ type AccountCustomerIDRequest struct {
 }
 
 type OrderAccountRequest struct {
-	AccountNumber string  `json:"account_number" valid:"notnull" `
-	Amount        float64 `json:"amount" valid:"notnull"`
+	AccountNumber string      `json:"account_number" valid:"notnull" `
+	Amount        json.Number `json:"amount" valid:"notnull"`
+	Currency      string      `json:"currency" valid:"optional"`
 }
 
 type TransferAccountRequest struct {
-	FromAccountNumber string  `json:"from_account" valid:"notnull" `
-	ToAccountNumber   string  `json:"to_account" valid:"notnull" `
-	Amount            float64 `json:"amount" valid:"notnull"`
+	FromAccountNumber string      `json:"from_account" valid:"notnull" `
+	ToAccountNumber   string      `json:"to_account" valid:"notnull" `
+	Amount            json.Number `json:"amount" valid:"notnull"`
+	Currency          string      `json:"currency" valid:"optional"`
 }
 
 type CreateAccountResponse struct {
-	AccountNumber string    `json:"account_number"`
-	AccountType   string    `json:"account_type"`
-	AccountID     string    `json:"Account_id"`
-	Name          string    `json:"name"`
-	Balance       float64   `json:"balance"`
-	Limit         float64   `json:"limit"`
-	CreatedAt     time.Time `json:"created_at"`
+	AccountNumber string       `json:"account_number"`
+	AccountType   string       `json:"account_type"`
+	AccountID     string       `json:"Account_id"`
+	Name          string       `json:"name"`
+	Balance       domain.Money `json:"balance"`
+	Limit         domain.Money `json:"limit"`
+	Currency      string       `json:"currency"`
+	CreatedAt     time.Time    `json:"created_at"`
 }
 
 type AccountResponse struct {
-	AccountNumber string  `json:"account_number"`
-	AccountType   string  `json:"account_type"`
-	Name          string  `json:"name"`
-	Balance       float64 `json:"balance"`
-	Limit         float64 `json:"limit"`
+	AccountNumber string       `json:"account_number"`
+	AccountType   string       `json:"account_type"`
+	CustomerID    string       `json:"customer_id,omitempty"`
+	Name          string       `json:"name"`
+	Balance       domain.Money `json:"balance"`
+	Limit         domain.Money `json:"limit"`
+	Currency      string       `json:"currency"`
+	Status        string       `json:"status"`
+}
+
+type ProductResponse struct {
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	DefaultLimit string   `json:"default_limit"`
+	MaxLimit     string   `json:"max_limit"`
+	Currencies   []string `json:"currencies"`
+}
+
+func NewProductResponse(p domain.Product) ProductResponse {
+	return ProductResponse{
+		Code:         p.Code,
+		Name:         p.Name,
+		DefaultLimit: p.DefaultLimit,
+		MaxLimit:     p.MaxLimit,
+		Currencies:   p.Currencies,
+	}
 }
 
 type AccountPresenter struct {
@@ -59,3 +88,79 @@ func NewAccountPresenter() *AccountPresenter {
 		logger: utils.NewLogger("presenter"),
 	}
 }
+
+type StatementRequest struct {
+	AccountNumber string `json:"account_number" valid:"notnull"`
+	Cursor        string `json:"cursor" valid:"optional"`
+	Limit         int    `json:"limit" valid:"optional"`
+	From          string `json:"from" valid:"optional"`
+	To            string `json:"to" valid:"optional"`
+	Types         string `json:"type" valid:"optional"`
+}
+
+type StatementLine struct {
+	ID             string       `json:"id"`
+	Type           string       `json:"type"`
+	Direction      string       `json:"direction"`
+	Amount         domain.Money `json:"amount"`
+	RunningBalance domain.Money `json:"running_balance"`
+	Counterparty   string       `json:"counterparty,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+type StatementResponse struct {
+	AccountNumber string          `json:"account_number"`
+	Currency      string          `json:"currency"`
+	Lines         []StatementLine `json:"lines"`
+	NextCursor    string          `json:"next_cursor,omitempty"`
+}
+
+type ExportStatementRequest struct {
+	AccountNumber string `json:"account_number" valid:"notnull"`
+	Format        string `json:"format" valid:"in(csv|ofx|txt)"`
+	From          string `json:"from" valid:"optional"`
+	To            string `json:"to" valid:"optional"`
+}
+
+// AdjustLimitRequest sets the size of an account's credit line, in the
+// account's currency.
+type AdjustLimitRequest struct {
+	AccountNumber string      `json:"account_number" valid:"notnull"`
+	Limit         json.Number `json:"limit" valid:"notnull"`
+}
+
+type ReversalRequest struct {
+	TransactionID string `json:"transaction_id" valid:"notnull"`
+}
+
+type RefundRequest struct {
+	TransactionID string      `json:"transaction_id" valid:"notnull"`
+	Amount        json.Number `json:"amount" valid:"notnull"`
+	Currency      string      `json:"currency" valid:"optional"`
+}
+
+type TransactionResponse struct {
+	ID            string       `json:"id"`
+	OperationID   string       `json:"operation_id"`
+	ReferenceID   string       `json:"reference_id,omitempty"`
+	AccountNumber string       `json:"account_number"`
+	Type          string       `json:"type"`
+	Amount        domain.Money `json:"amount"`
+	BalanceAfter  domain.Money `json:"balance_after"`
+	Currency      string       `json:"currency"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+func NewTransactionResponse(tx domain.Transaction) TransactionResponse {
+	return TransactionResponse{
+		ID:            tx.ID,
+		OperationID:   tx.OperationID,
+		ReferenceID:   tx.ReferenceID,
+		AccountNumber: tx.AccountNumber,
+		Type:          string(tx.Type),
+		Amount:        tx.Amount,
+		BalanceAfter:  tx.BalanceAfter,
+		Currency:      tx.Amount.Currency(),
+		CreatedAt:     tx.CreatedAt,
+	}
+}
@@ -0,0 +1,96 @@
+package presenter
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+)
+
+type IssueCardRequest struct {
+	AccountNumber string `json:"account_number" valid:"notnull"`
+	Brand         string `json:"brand" valid:"in(visa|mastercard|amex|discover)"`
+}
+
+type CardIDRequest struct {
+	CardID string `json:"card_id" valid:"notnull"`
+}
+
+type CardResponse struct {
+	ID              string    `json:"id"`
+	AccountNumber   string    `json:"account_number"`
+	Brand           string    `json:"brand"`
+	MaskedPAN       string    `json:"masked_pan"`
+	ExpirationMonth int       `json:"expiration_month"`
+	ExpirationYear  int       `json:"expiration_year"`
+	Status          string    `json:"status"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// IssueCardResponse is the only response that ever contains the full card
+// number and CCV.
+type IssueCardResponse struct {
+	CardResponse
+	PAN string `json:"pan"`
+	CCV string `json:"ccv"`
+}
+
+func NewCardResponse(c *domain.Card) CardResponse {
+	return CardResponse{
+		ID:              c.ID,
+		AccountNumber:   c.AccountNumber,
+		Brand:           c.Brand,
+		MaskedPAN:       c.MaskedPAN,
+		ExpirationMonth: c.ExpirationMonth,
+		ExpirationYear:  c.ExpirationYear,
+		Status:          string(c.Status),
+		CreatedAt:       c.CreatedAt,
+	}
+}
+
+type AuthorizeRequest struct {
+	PAN             string      `json:"pan" valid:"numeric"`
+	CCV             string      `json:"ccv" valid:"numeric"`
+	ExpirationMonth int         `json:"expiration_month" valid:"required"`
+	ExpirationYear  int         `json:"expiration_year" valid:"required"`
+	Merchant        string      `json:"merchant" valid:"notnull"`
+	Amount          json.Number `json:"amount" valid:"notnull"`
+	Currency        string      `json:"currency" valid:"optional"`
+}
+
+type CaptureRequest struct {
+	AuthorizationID string      `json:"authorization_id" valid:"notnull"`
+	Amount          json.Number `json:"amount" valid:"optional"`
+}
+
+type AuthorizationIDRequest struct {
+	AuthorizationID string `json:"authorization_id" valid:"notnull"`
+}
+
+type AuthorizationResponse struct {
+	ID            string       `json:"id"`
+	CardID        string       `json:"card_id"`
+	Merchant      string       `json:"merchant"`
+	Amount        domain.Money `json:"amount"`
+	Captured      domain.Money `json:"captured"`
+	Currency      string       `json:"currency"`
+	Status        string       `json:"status"`
+	TransactionID string       `json:"transaction_id,omitempty"`
+	ExpiresAt     time.Time    `json:"expires_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+func NewAuthorizationResponse(a *domain.Authorization) AuthorizationResponse {
+	return AuthorizationResponse{
+		ID:            a.ID,
+		CardID:        a.CardID,
+		Merchant:      a.Merchant,
+		Amount:        a.Amount,
+		Captured:      a.Captured,
+		Currency:      a.Amount.Currency(),
+		Status:        string(a.Status),
+		TransactionID: a.TransactionID,
+		ExpiresAt:     a.ExpiresAt,
+		CreatedAt:     a.CreatedAt,
+	}
+}
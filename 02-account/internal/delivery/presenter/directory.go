@@ -0,0 +1,25 @@
+package presenter
+
+import "time"
+
+// CustomerResponse is a customer as the customer service's directory returns
+// it.
+type CustomerResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TokenCheckRequest asks whether an access token, identified by its jti,
+// subject and issue time (Unix microseconds), has been revoked.
+type TokenCheckRequest struct {
+	JTI            string `json:"jti"`
+	CustomerID     string `json:"customer_id"`
+	IssuedAtMicros int64  `json:"issued_at_us"`
+}
+
+type TokenCheckResponse struct {
+	Revoked bool `json:"revoked"`
+}
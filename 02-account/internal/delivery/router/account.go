@@ -1,48 +1,52 @@
 package router
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
+	"time"
 
-	"github.com/adilsonmenechini/golabbank/internal/account/repositories"
-	"github.com/adilsonmenechini/golabbank/internal/account/usecases"
-	"github.com/adilsonmenechini/golabbank/internal/delivery/handler"
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"github.com/adilsonmenechini/golabbank/account/config"
+	"github.com/adilsonmenechini/golabbank/account/internal/account/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/account/usecases"
+	"github.com/adilsonmenechini/golabbank/account/internal/customer/directory"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/handler"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	idemrepo "github.com/adilsonmenechini/golabbank/account/internal/idempotency/repositories"
+	"github.com/adilsonmenechini/golabbank/account/pkg/genrand"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/identity"
+	"github.com/adilsonmenechini/golabbank/platform/metrics"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
 	"github.com/gorilla/mux"
 )
 
+// accountNumberAttempts bounds how many random numbers are tried for one new
+// account before giving up.
+const accountNumberAttempts = 8
+
 type AccountRouter struct {
-	hdl    handler.AccountHandler
-	logger *utils.Logger
+	hdl     handler.AccountHandler
+	auth    *authenticator
+	idem    *idempotency
+	numbers *genrand.AccountNumberAllocator
+	logger  *utils.Logger
 }
 
-func NewAccountRouter(hdlr handler.AccountHandler) *AccountRouter {
+func NewAccountRouter(hdlr handler.AccountHandler, auth *authenticator, idem *idempotency, numbers *genrand.AccountNumberAllocator) *AccountRouter {
 	return &AccountRouter{
-		hdl:    hdlr,
-		logger: utils.NewLogger("Router"),
+		hdl:     hdlr,
+		auth:    auth,
+		idem:    idem,
+		numbers: numbers,
+		logger:  utils.NewLogger("Router"),
 	}
 }
 
-func jwtMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, err := utils.GetTokenAuthorization(r)
-		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"status":     "error",
-				"statusCode": http.StatusUnauthorized,
-				"message":    "Unauthorized",
-			})
-
-			return
-		}
-		next.ServeHTTP(w, r)
-
-	})
-}
-
 func (ra *AccountRouter) account() http.Handler {
 	r := mux.NewRouter()
 	c := r.PathPrefix("/api/account").Subrouter()
@@ -54,21 +58,67 @@ func (ra *AccountRouter) account() http.Handler {
 	})
 
 	a.HandleFunc("/create", ra.hdl.CreateAccountHandler).Methods("POST")
-	a.HandleFunc("/deposit", ra.hdl.DepositHandler).Methods("POST")
-	a.HandleFunc("/withdraw", ra.hdl.WithdrawHandler).Methods("POST")
-	a.HandleFunc("/transfer", ra.hdl.TransferHandler).Methods("POST")
-	a.HandleFunc("/payment", ra.hdl.PaymentHandler).Methods("POST")
-	a.HandleFunc("/balance", ra.hdl.PaymentLimitHandler).Methods("POST")
-	a.Use(jwtMiddleware)
+	a.HandleFunc("/deposit", ra.idem.wrap(ra.hdl.DepositHandler)).Methods("POST")
+	a.HandleFunc("/withdraw", ra.idem.wrap(ra.hdl.WithdrawHandler)).Methods("POST")
+	a.HandleFunc("/transfer", ra.idem.wrap(ra.hdl.TransferHandler)).Methods("POST")
+	a.HandleFunc("/payment", ra.idem.wrap(ra.hdl.PaymentHandler)).Methods("POST")
+	a.HandleFunc("/balance", ra.idem.wrap(ra.hdl.PaymentLimitHandler)).Methods("POST")
+	a.HandleFunc("/products", ra.hdl.ListProductsHandler).Methods("GET")
+	a.HandleFunc("/accounts", ra.hdl.ListAccountsHandler).Methods("GET")
+	a.HandleFunc("/accounts/{account_number}", ra.hdl.GetAccountHandler).Methods("GET")
+	a.HandleFunc("/accounts/{account_number}", ra.hdl.CloseOwnAccountHandler).Methods("DELETE")
+	a.HandleFunc("/accounts/{account_number}/statement", ra.hdl.StatementHandler).Methods("GET")
+	a.HandleFunc("/accounts/{account_number}/statement/export", ra.hdl.ExportStatementHandler).Methods("GET")
+	a.Use(ra.auth.jwtMiddleware)
+
+	// Back office. Every route declares the roles allowed on it; the staff
+	// tokens come from the same sign-in as customers'.
+	staff := []identity.Role{identity.RoleAdmin, identity.RoleTeller, identity.RoleAuditor}
+	counter := []identity.Role{identity.RoleAdmin, identity.RoleTeller}
+	adm := a.PathPrefix("/admin").Subrouter()
+	adm.Handle("/accounts/{account_number}", ra.auth.roles.Allow(ra.hdl.InspectAccountHandler, staff...)).Methods("GET")
+	adm.Handle("/accounts/{account_number}/deposit", ra.auth.roles.Allow(ra.idem.wrap(ra.hdl.CashDepositHandler), counter...)).Methods("POST")
+	adm.Handle("/accounts/{account_number}/withdraw", ra.auth.roles.Allow(ra.idem.wrap(ra.hdl.CashWithdrawHandler), counter...)).Methods("POST")
+	adm.Handle("/accounts/{account_number}/limit", ra.auth.roles.Allow(ra.hdl.AdjustLimitHandler, identity.RoleAdmin)).Methods("PUT")
+	adm.Handle("/accounts/{account_number}/activate", ra.auth.roles.Allow(ra.hdl.ActivateAccountHandler, counter...)).Methods("POST")
+	adm.Handle("/accounts/{account_number}/freeze", ra.auth.roles.Allow(ra.hdl.FreezeAccountHandler, identity.RoleAdmin)).Methods("POST")
+	adm.Handle("/accounts/{account_number}/unfreeze", ra.auth.roles.Allow(ra.hdl.UnfreezeAccountHandler, identity.RoleAdmin)).Methods("POST")
+	adm.Handle("/accounts/{account_number}/close", ra.auth.roles.Allow(ra.hdl.CloseAccountHandler, identity.RoleAdmin)).Methods("POST")
+	adm.Handle("/transactions/{transaction_id}/reverse", ra.auth.roles.Allow(ra.hdl.ReverseTransactionHandler, identity.RoleAdmin)).Methods("POST")
+	adm.Handle("/transactions/{transaction_id}/refund", ra.auth.roles.Allow(ra.hdl.RefundTransactionHandler, identity.RoleAdmin)).Methods("POST")
+	adm.Handle("/account-numbers", ra.auth.roles.Allow(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ra.numbers.Stats())
+	}, identity.RoleAdmin, identity.RoleAuditor)).Methods("GET")
+	r.Use(metrics.Route, tracing.Route)
 
 	return r
 }
 
-func AccountImpl(db *sql.DB) http.Handler {
+func loadCatalog(path string) (*domain.Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return domain.LoadCatalog(f)
+}
+
+func AccountImpl(ctx context.Context, db *sql.DB, auth *authenticator, customers directory.CustomerDirectory) (http.Handler, error) {
+	catalog, err := loadCatalog(config.ProductsFile())
+	if err != nil {
+		return nil, fmt.Errorf("loading account products: %w", err)
+	}
+
+	numbers := genrand.NewAccountNumberAllocator(accountNumberAttempts)
+
 	repoC := repositories.NewAccountRepository(db)
-	uscC := usecases.NewAccountUseCase(repoC)
+	uscC := usecases.NewAccountUseCase(repoC, repositories.NewTransactionRepository(db), audit.NewLogAuditor(), customers, catalog, numbers)
 	hdlC := handler.NewAccountHandler(uscC)
-	rc := NewAccountRouter(hdlC).account()
 
-	return rc
+	idem := newIdempotency(idemrepo.NewIdempotencyRepository(db), config.GetDuration("IDEMPOTENCY_TTL", 24*time.Hour))
+	go idem.sweep(ctx, time.Hour)
+
+	rc := NewAccountRouter(hdlC, auth, idem, numbers).account()
+
+	return rc, nil
 }
@@ -0,0 +1,51 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/customer/directory"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/rbac"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// authenticator validates bearer tokens and asks the customer directory
+// whether they were revoked before letting a request through.
+type authenticator struct {
+	customers directory.CustomerDirectory
+	roles     *rbac.Enforcer
+	rs        *response.Presenter
+	logger    *utils.Logger
+}
+
+func newAuthenticator(customers directory.CustomerDirectory) *authenticator {
+	return &authenticator{
+		customers: customers,
+		roles:     rbac.NewEnforcer(audit.NewLogAuditor()),
+		rs:        response.NewPresenter(),
+		logger:    utils.NewLogger("Auth"),
+	}
+}
+
+func (au *authenticator) jwtMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tk, err := utils.GetTokenAuthorization(r)
+		if err != nil {
+			au.rs.ResponseError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if err := au.customers.CheckToken(r.Context(), tk); err != nil {
+			if errors.Is(err, domain.ErrTokenRevoked) {
+				au.rs.ResponseError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+			au.logger.Errorf("error checking token revocation: %v", err)
+			au.rs.ResponseError(w, http.StatusServiceUnavailable, "Service Unavailable")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(utils.ContextWithClaims(r.Context(), tk)))
+	})
+}
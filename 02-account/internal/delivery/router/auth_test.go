@@ -0,0 +1,108 @@
+package router
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/customer/directory"
+	"github.com/adilsonmenechini/golabbank/account/internal/customer/directory/directorytest"
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/keyring"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// writeEd25519Key stores a fresh signing key the way JWT_PRIVATE_KEY_FILE
+// expects it.
+func writeEd25519Key(t *testing.T) string {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestCustomerServiceTokensAuthenticateHere signs tokens with the customer
+// service's configuration and publishes its keys the way its router does,
+// then sends them through this service's authenticator configured with
+// JWT_JWKS_URL only.
+func TestCustomerServiceTokensAuthenticateHere(t *testing.T) {
+	t.Setenv("JWT_ISSUER", "golabbank")
+	t.Setenv("JWT_AUDIENCE", "golabbank")
+	t.Setenv("JWT_SECRET", "")
+
+	// The customer service.
+	t.Setenv("JWT_PRIVATE_KEY_FILE", writeEd25519Key(t))
+	customerKeys, err := keyring.FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(customerKeys.JWKS())
+	}))
+	defer jwks.Close()
+	customers := directorytest.NewServer("service-key", domain.Customer{ID: "alice"})
+	defer customers.Close()
+
+	// A key the customer service never published.
+	t.Setenv("JWT_PRIVATE_KEY_FILE", writeEd25519Key(t))
+	foreignKeys, err := keyring.FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// This service: no signing key, the customer service's JWKS.
+	t.Setenv("JWT_PRIVATE_KEY_FILE", "")
+	t.Setenv("JWT_JWKS_URL", jwks.URL)
+	if _, err := utils.TokenKeys(); err != nil {
+		t.Fatal(err)
+	}
+
+	var seen utils.Claims
+	protected := newAuthenticator(directory.NewHTTPDirectory(customers.Config())).jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = utils.ClaimsFromContext(r.Context())
+	}))
+	call := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/account/v1/accounts", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		protected.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	token, err := utils.SignAccessToken(customerKeys, "alice", "Alice", "alice@example.com", "teller")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := call(token); code != http.StatusOK {
+		t.Fatalf("customer service token: status %d, want 200", code)
+	}
+	if seen.ID != "alice" || seen.Name != "Alice" || seen.Role != "teller" {
+		t.Fatalf("claims seen here = %+v", seen)
+	}
+
+	forged, err := utils.SignAccessToken(foreignKeys, "alice", "Alice", "alice@example.com", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := call(forged); code != http.StatusUnauthorized {
+		t.Fatalf("token signed with an unpublished key: status %d, want 401", code)
+	}
+}
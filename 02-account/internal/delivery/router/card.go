@@ -0,0 +1,101 @@
+package router
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/config"
+	accrepo "github.com/adilsonmenechini/golabbank/account/internal/account/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/card/repositories"
+	"github.com/adilsonmenechini/golabbank/account/internal/card/usecases"
+	"github.com/adilsonmenechini/golabbank/account/internal/delivery/handler"
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/metrics"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/gorilla/mux"
+)
+
+type CardRouter struct {
+	hdl    handler.CardHandler
+	auth   handler.AuthorizationHandler
+	authn  *authenticator
+	logger *utils.Logger
+}
+
+func NewCardRouter(hdlr handler.CardHandler, auth handler.AuthorizationHandler, authn *authenticator) *CardRouter {
+	return &CardRouter{
+		hdl:    hdlr,
+		auth:   auth,
+		authn:  authn,
+		logger: utils.NewLogger("Router"),
+	}
+}
+
+func (rc *CardRouter) card() http.Handler {
+	r := mux.NewRouter()
+	c := r.PathPrefix("/api/card").Subrouter()
+
+	a := c.PathPrefix("/v1").Subrouter()
+
+	h := a.PathPrefix("/cards").Subrouter()
+	h.HandleFunc("", rc.hdl.IssueCardHandler).Methods("POST")
+	h.HandleFunc("", rc.hdl.ListCardsHandler).Methods("GET")
+	h.HandleFunc("/{card_id}/block", rc.hdl.BlockCardHandler).Methods("POST")
+	h.HandleFunc("/{card_id}/unblock", rc.hdl.UnblockCardHandler).Methods("POST")
+	h.Use(rc.authn.jwtMiddleware)
+
+	m := a.PathPrefix("/authorizations").Subrouter()
+	m.HandleFunc("", rc.auth.AuthorizeHandler).Methods("POST")
+	m.HandleFunc("/{authorization_id}", rc.auth.GetAuthorizationHandler).Methods("GET")
+	m.HandleFunc("/{authorization_id}/capture", rc.auth.CaptureHandler).Methods("POST")
+	m.HandleFunc("/{authorization_id}/void", rc.auth.VoidHandler).Methods("POST")
+	m.Use(merchantMiddleware)
+	r.Use(metrics.Route, tracing.Route)
+
+	return r
+}
+
+// sweepAuthorizations releases the holds of authorizations that expired
+// without being captured, every interval until ctx is done.
+func (rc *CardRouter) sweepAuthorizations(ctx context.Context, usc usecases.AuthorizationUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := usc.ExpirePending(ctx)
+			if err != nil {
+				rc.logger.WithContext(ctx).Errorf("error expiring card authorizations: %v", err)
+				continue
+			}
+			if n > 0 {
+				rc.logger.WithContext(ctx).Infof("expired %d card authorizations", n)
+			}
+		}
+	}
+}
+
+func CardImpl(ctx context.Context, db *sql.DB, authn *authenticator) http.Handler {
+	panKey := []byte(os.Getenv("CARD_PAN_KEY"))
+	auditor := audit.NewLogAuditor()
+
+	repoC := repositories.NewCardRepository(db)
+	uscC := usecases.NewCardUseCase(repoC, accrepo.NewAccountRepository(db), auditor, panKey)
+	hdlC := handler.NewCardHandler(uscC)
+
+	uscA := usecases.NewAuthorizationUseCase(repoC, repositories.NewAuthorizationRepository(db), auditor, panKey,
+		config.GetDuration("CARD_AUTHORIZATION_TTL", 7*24*time.Hour),
+		config.GetInt("CARD_MAX_VERIFY_ATTEMPTS", 3))
+	hdlA := handler.NewAuthorizationHandler(uscA)
+
+	rc := NewCardRouter(hdlC, hdlA, authn)
+	go rc.sweepAuthorizations(ctx, uscA, time.Minute)
+
+	return rc.card()
+}
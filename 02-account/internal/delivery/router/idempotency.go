@@ -0,0 +1,141 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/account/internal/idempotency/repositories"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+const idempotencyHeader = "Idempotency-Key"
+
+type idempotency struct {
+	repo   repositories.IdempotencyRepository
+	ttl    time.Duration
+	rs     *response.Presenter
+	logger *utils.Logger
+}
+
+func newIdempotency(repo repositories.IdempotencyRepository, ttl time.Duration) *idempotency {
+	return &idempotency{
+		repo:   repo,
+		ttl:    ttl,
+		rs:     response.NewPresenter(),
+		logger: utils.NewLogger("Idempotency"),
+	}
+}
+
+// wrap makes next safe to retry. The first response for a customer and
+// Idempotency-Key is stored and replayed for later requests carrying the same
+// key and body; a different body under the same key is rejected with 422.
+// Requests without the header pass straight through.
+func (i *idempotency) wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		tk, ok := utils.ClaimsFromContext(r.Context())
+		if !ok {
+			i.rs.ResponseError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			i.rs.ResponseError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(append([]byte(r.Method+" "+r.URL.Path+"\n"), body...))
+		hash := hex.EncodeToString(sum[:])
+
+		rec, err := i.repo.Reserve(r.Context(), tk.ID, key, hash, i.ttl)
+		if err != nil {
+			i.logger.Errorf("error reserving idempotency key: %v", err)
+			i.rs.ResponseError(w, http.StatusInternalServerError, "could not reserve idempotency key")
+			return
+		}
+
+		if rec != nil {
+			switch {
+			case rec.RequestHash != hash:
+				i.rs.ResponseError(w, http.StatusUnprocessableEntity, domain.ErrIdempotencyKeyReused.Error())
+			case rec.Status != domain.IdempotencyCompleted:
+				i.rs.ResponseError(w, http.StatusConflict, domain.ErrIdempotencyKeyInProgress.Error())
+			default:
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(rec.StatusCode)
+				w.Write(rec.ResponseBody)
+			}
+			return
+		}
+
+		rw := &recordingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next(rw, r)
+
+		// The outcome is persisted even if the client has gone away, which is
+		// exactly the case a retry needs to see.
+		ctx := context.WithoutCancel(r.Context())
+		if rw.statusCode >= http.StatusInternalServerError {
+			// Server failures are not replayed: let the client retry for real.
+			if err := i.repo.Release(ctx, tk.ID, key); err != nil {
+				i.logger.Errorf("error releasing idempotency key: %v", err)
+			}
+			return
+		}
+		if err := i.repo.Complete(ctx, tk.ID, key, rw.statusCode, rw.body.Bytes()); err != nil {
+			i.logger.Errorf("error storing idempotent response: %v", err)
+		}
+	}
+}
+
+// sweep periodically removes expired keys until ctx is cancelled.
+func (i *idempotency) sweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := i.repo.DeleteExpired(ctx)
+			if err != nil {
+				i.logger.Errorf("error deleting expired idempotency keys: %v", err)
+				continue
+			}
+			if n > 0 {
+				i.logger.Infof("deleted %d expired idempotency keys", n)
+			}
+		}
+	}
+}
+
+// recordingWriter passes the response through while keeping a copy of it.
+type recordingWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(statusCode int) {
+	rw.statusCode = statusCode
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
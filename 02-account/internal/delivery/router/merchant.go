@@ -0,0 +1,33 @@
+package router
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/gorilla/mux"
+)
+
+const merchantKeyHeader = "X-Merchant-Key"
+
+// merchantMiddleware restricts the card authorization routes to acquirers
+// presenting the MERCHANT_API_KEY shared secret.
+var merchantMiddleware = sharedKeyMiddleware(merchantKeyHeader, "MERCHANT_API_KEY")
+
+// sharedKeyMiddleware compares header against the secret held in the env
+// variable. When no key is configured every request is refused.
+func sharedKeyMiddleware(header, env string) mux.MiddlewareFunc {
+	rs := response.NewPresenter()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			want := os.Getenv(env)
+			got := r.Header.Get(header)
+			if want == "" || subtle.ConstantTimeCompare([]byte(want), []byte(got)) != 1 {
+				rs.ResponseError(w, http.StatusForbidden, "Forbidden")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
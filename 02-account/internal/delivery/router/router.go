@@ -1,30 +1,58 @@
 package router
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
 	"time"
 
+	"github.com/adilsonmenechini/golabbank/account/config"
+	"github.com/adilsonmenechini/golabbank/account/internal/customer/directory"
+	"github.com/adilsonmenechini/golabbank/platform/metrics"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
 	"github.com/gorilla/mux"
 )
 
-func Router(db *sql.DB) {
+// ErrNoCustomerService is returned when CUSTOMER_SERVICE_URL is unset: the
+// account service has no customers of its own to fall back on.
+var ErrNoCustomerService = errors.New("CUSTOMER_SERVICE_URL is not set")
+
+// Router builds the handler of the account and card APIs. Customers and their
+// tokens belong to the customer service at CUSTOMER_SERVICE_URL and are
+// resolved over HTTP. Background sweeps run until ctx is done.
+func Router(ctx context.Context, db *sql.DB) (http.Handler, error) {
+
+	if _, err := utils.TokenKeys(); err != nil {
+		return nil, fmt.Errorf("loading token keys: %w", err)
+	}
+	url := os.Getenv("CUSTOMER_SERVICE_URL")
+	if url == "" {
+		return nil, ErrNoCustomerService
+	}
 
-	rcustomer := CustomerImpl(db)
-	raccount := AccountImpl(db)
 	r := mux.NewRouter()
+	r.Handle("/metrics", metrics.Handler()).Methods("GET")
 
-	r.PathPrefix("/api/account/v1").Handler(raccount)
-	r.PathPrefix("/api/customer/v1").Handler(rcustomer)
-
-	// Crie o servidor HTTP usando o roteador principal
-	srv := &http.Server{
-		Handler:      r,
-		Addr:         "127.0.0.1:8000",
-		WriteTimeout: 15 * time.Second,
-		ReadTimeout:  15 * time.Second,
+	customers := directory.NewHTTPDirectory(directory.HTTPConfig{
+		BaseURL:     url,
+		ServiceKey:  os.Getenv("CUSTOMER_SERVICE_KEY"),
+		Timeout:     config.GetDuration("CUSTOMER_SERVICE_TIMEOUT", 2*time.Second),
+		CustomerTTL: config.GetDuration("CUSTOMER_CACHE_TTL", 5*time.Minute),
+		TokenTTL:    config.GetDuration("CUSTOMER_TOKEN_CACHE_TTL", 15*time.Second),
+	})
+	auth := newAuthenticator(customers)
+
+	raccount, err := AccountImpl(ctx, db, auth, customers)
+	if err != nil {
+		return nil, err
 	}
+	rcard := CardImpl(ctx, db, auth)
+
+	r.PathPrefix("/api/account/v1").Handler(raccount)
+	r.PathPrefix("/api/card/v1").Handler(rcard)
 
-	log.Fatal(srv.ListenAndServe())
+	return utils.RequestID(r), nil
 }
@@ -1,164 +1,450 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
-	"sync"
 	"time"
+)
+
+var (
+	ErrUnauthenticated   = errors.New("authentication required")
+	ErrAccountForbidden  = errors.New("account does not belong to the authenticated customer")
+	ErrAccountPending    = errors.New("account is not active yet")
+	ErrAccountFrozen     = errors.New("account is frozen")
+	ErrAccountClosed     = errors.New("account is closed")
+	ErrAccountNotSettled = errors.New("account must have a zero balance and a fully repaid limit to be closed")
+	ErrInvalidTransition = errors.New("invalid account status transition")
+	ErrLimitBelowUsed    = errors.New("credit limit below the credit already in use")
+)
+
+type AccountStatus string
 
-	"github.com/adilsonmenechini/golabbank/pkg/genrand"
+const (
+	AccountPending AccountStatus = "pending"
+	AccountActive  AccountStatus = "active"
+	AccountFrozen  AccountStatus = "frozen"
+	AccountClosed  AccountStatus = "closed"
 )
 
+// accountTransitions lists the statuses each status may move to. Closed is
+// final: accounts with history are never deleted, only closed.
+var accountTransitions = map[AccountStatus][]AccountStatus{
+	AccountPending: {AccountActive, AccountClosed},
+	AccountActive:  {AccountFrozen, AccountClosed},
+	AccountFrozen:  {AccountActive, AccountClosed},
+}
+
 type Account struct {
 	AccountNumber string
 	AccountType   string
 	CustomerID    string
 	Name          string
-	Balance       float64
-	Limit         float64
-	Reversal      float64
+	Balance       Money
+	Limit         Money
+	Reversal      Money
+	Status        AccountStatus
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
-	Mu            sync.Mutex
 }
 
-func checkAccountType(accType string, inLimit float64) float64 {
-	var limit float64
-
-	switch accType {
-	case "bb":
-		limit = 500
-	case "itau":
-		limit = 1000
-	case "caixa":
-		limit = 1000
-	case "santander":
-		limit = 200
-	default:
-		limit = 0
+// NewAccount opens an account of product for cr. currency defaults to the
+// product's first currency; a positive inLimit overrides the product's default
+// credit limit up to its maximum. The account number is left empty: it is
+// allocated when the account is stored, see genrand.AccountNumberAllocator.
+// The account starts pending and takes no business until the back office
+// activates it.
+func NewAccount(cr *Customer, product Product, currency string, inLimit Money) (*Account, error) {
+	if currency == "" {
+		currency = product.Currencies[0]
 	}
-	if inLimit > 0 {
+	if !product.Offers(currency) {
+		return nil, fmt.Errorf("%w: %s does not offer %s", ErrCurrencyNotOffered, product.Code, currency)
+	}
+	limit, max, err := product.Limits(currency)
+	if err != nil {
+		return nil, err
+	}
+	if inLimit.IsPositive() {
+		if err := checkCurrencyOf(inLimit, currency); err != nil {
+			return nil, err
+		}
+		if c, err := inLimit.Cmp(max); err != nil {
+			return nil, err
+		} else if c > 0 {
+			return nil, fmt.Errorf("%w: %s allows up to %s", ErrLimitAboveProductMax, product.Code, max)
+		}
 		limit = inLimit
 	}
-	return limit
+	return &Account{
+		AccountType: product.Code,
+		CustomerID:  cr.ID,
+		Name:        cr.Name,
+		Balance:     NewMoney(0, currency),
+		Limit:       limit,
+		Reversal:    limit,
+		Status:      AccountPending,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}, nil
+}
 
+// Activate opens a pending account for business.
+func (a *Account) Activate() error {
+	if a.Status != AccountPending {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, a.Status, AccountActive)
+	}
+	return a.moveTo(AccountActive)
 }
-func NewAccount(cr *Customer, accType string, inLimit float64) *Account {
-	limit := checkAccountType(accType, inLimit)
-	acc := genrand.GenerateAcoount(accType)
 
-	return &Account{
-		AccountNumber: acc.CardNumber,
-		AccountType:   string(acc.AccountType),
-		CustomerID:    cr.ID,
-		Name:          cr.Name,
-		Balance:       0,
-		Limit:         limit,
-		Reversal:      limit,
-		CreatedAt:     time.Now().UTC(),
-		UpdatedAt:     time.Now().UTC(),
+// Freeze stops all customer activity on the account.
+func (a *Account) Freeze() error {
+	return a.moveTo(AccountFrozen)
+}
+
+// Unfreeze returns a frozen account to active.
+func (a *Account) Unfreeze() error {
+	if a.Status != AccountFrozen {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, a.Status, AccountActive)
+	}
+	return a.moveTo(AccountActive)
+}
+
+// Close closes the account for good. The balance must be zero and the credit
+// line fully repaid, which also means no card hold is outstanding.
+func (a *Account) Close() error {
+	if c, err := a.Limit.Cmp(a.Reversal); err != nil || c != 0 || !a.Balance.IsZero() {
+		return ErrAccountNotSettled
+	}
+	return a.moveTo(AccountClosed)
+}
+
+func (a *Account) moveTo(to AccountStatus) error {
+	for _, next := range accountTransitions[a.Status] {
+		if next == to {
+			a.Status = to
+			a.UpdatedAt = time.Now().UTC()
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, a.Status, to)
+}
+
+// checkActive guards customer-initiated mutations, which need an active
+// account.
+func (a *Account) checkActive() error {
+	switch a.Status {
+	case AccountActive:
+		return nil
+	case AccountPending:
+		return ErrAccountPending
+	case AccountFrozen:
+		return ErrAccountFrozen
+	default:
+		return ErrAccountClosed
+	}
+}
+
+// checkNotClosed guards compensating mutations (refunds, reversals, released
+// holds). They must still go through on a frozen account so back-office
+// corrections are not blocked by the freeze itself.
+func (a *Account) checkNotClosed() error {
+	if a.Status == AccountClosed {
+		return ErrAccountClosed
 	}
+	return nil
 }
 
-func (a *Account) Deposit(amount float64) error {
-	a.Mu.Lock()
-	defer a.Mu.Unlock()
-	if amount > 0 {
-		a.Balance += amount
-		a.UpdatedAt = time.Now().UTC()
+// SetCreditLimit resizes the credit line to line, up to the product's max.
+// Credit already drawn stays drawn, so line cannot be smaller than it.
+func (a *Account) SetCreditLimit(line, max Money) error {
+	if err := a.checkNotClosed(); err != nil {
+		return err
+	}
+	if err := a.checkCurrency(line); err != nil {
+		return err
+	}
+	if line.IsNegative() {
+		return ErrInvalidAmount
+	}
+	if c, err := line.Cmp(max); err != nil {
+		return err
+	} else if c > 0 {
+		return fmt.Errorf("%w: %s allows up to %s", ErrLimitAboveProductMax, a.AccountType, max)
+	}
+	used, err := a.Reversal.Sub(a.Limit)
+	if err != nil {
+		return err
+	}
+	if c, _ := line.Cmp(used); c < 0 {
+		return fmt.Errorf("%w: %s in use", ErrLimitBelowUsed, used)
 	}
+	a.Limit, _ = line.Sub(used)
+	a.Reversal = line
+	a.UpdatedAt = time.Now().UTC()
 	return nil
 }
 
-func (a *Account) Withdraw(amount float64) error {
-	a.Mu.Lock()
-	defer a.Mu.Unlock()
+// Currency is the currency every amount applied to the account must use.
+func (a *Account) Currency() string {
+	return a.Balance.Currency()
+}
+
+func (a *Account) checkCurrency(amount Money) error {
+	return checkCurrencyOf(amount, a.Currency())
+}
+
+func checkCurrencyOf(amount Money, currency string) error {
+	if amount.Currency() != currency {
+		return fmt.Errorf("%w: account is %s, amount is %s", ErrCurrencyMismatch, currency, amount.Currency())
+	}
+	return nil
+}
 
-	if amount >= 0 && amount > a.Balance {
-		return fmt.Errorf("%s", ErrWithdrawalInsufficient)
+// checkAmount rejects an amount in another currency than the account's, or
+// one that is not positive: the operation, not the sign, says which way the
+// money moves.
+func (a *Account) checkAmount(amount Money) error {
+	if err := a.checkCurrency(amount); err != nil {
+		return err
 	}
+	if !amount.IsPositive() {
+		return fmt.Errorf("%w: %s must be positive", ErrInvalidAmount, amount)
+	}
+	return nil
+}
 
-	a.Balance -= amount
+func (a *Account) Deposit(amount Money) error {
+	if err := a.checkActive(); err != nil {
+		return err
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	balance, err := a.Balance.Add(amount)
+	if err != nil {
+		return err
+	}
+	a.Balance = balance
 	a.UpdatedAt = time.Now().UTC()
 	return nil
 }
 
-func (a *Account) Payment(amount float64) error {
-	a.Mu.Lock()
-	defer a.Mu.Unlock()
-	var amountToPay = amount
-	if amountToPay > 0 {
-		if a.Balance+a.Limit >= amountToPay {
-			if a.Balance >= amountToPay {
-				a.Balance -= amountToPay
-				a.UpdatedAt = time.Now().UTC()
-				return nil
-			} else {
-				amountToPay -= a.Balance
-				a.Limit -= amountToPay
-				a.Balance = 0
-				a.UpdatedAt = time.Now().UTC()
-				return nil
-			}
-
-		} else {
-			return fmt.Errorf("%s", ErrPaymentInsufficient)
-		}
+func (a *Account) Withdraw(amount Money) error {
+	if err := a.checkActive(); err != nil {
+		return err
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	if c, _ := amount.Cmp(a.Balance); c > 0 {
+		return ErrWithdrawalInsufficient
+	}
+
+	a.Balance, _ = a.Balance.Sub(amount)
+	a.UpdatedAt = time.Now().UTC()
+	return nil
+}
 
+// Payment takes amount from the balance first and draws the rest on the
+// credit line.
+func (a *Account) Payment(amount Money) error {
+	if err := a.checkActive(); err != nil {
+		return err
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	available, err := a.Balance.Add(a.Limit)
+	if err != nil {
+		return err
+	}
+	if c, _ := available.Cmp(amount); c < 0 {
+		return ErrPaymentInsufficient
+	}
+	if c, _ := a.Balance.Cmp(amount); c >= 0 {
+		a.Balance, _ = a.Balance.Sub(amount)
 	} else {
-		return fmt.Errorf("%s", ErrPaymentInsufficient)
+		fromLimit, _ := amount.Sub(a.Balance)
+		a.Limit, _ = a.Limit.Sub(fromLimit)
+		a.Balance = NewMoney(0, a.Currency())
 	}
+	a.UpdatedAt = time.Now().UTC()
+	return nil
 }
 
-func (a *Account) PaymentLimit(amount float64) error {
-	a.Mu.Lock()
-	defer a.Mu.Unlock()
+func (a *Account) PaymentLimit(amount Money) error {
+	if err := a.checkActive(); err != nil {
+		return err
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	return a.restoreCredit(amount)
+}
 
-	var amountToPay = amount
+// restoreCredit pays back the credit line first, up to its original size,
+// and credits any surplus to the balance.
+func (a *Account) restoreCredit(amount Money) error {
+	limit, err := a.Limit.Add(amount)
+	if err != nil {
+		return err
+	}
+	if c, _ := limit.Cmp(a.Reversal); c >= 0 {
+		surplus, _ := limit.Sub(a.Reversal)
+		balance, err := a.Balance.Add(surplus)
+		if err != nil {
+			return err
+		}
+		a.Balance = balance
+		a.Limit = a.Reversal
+	} else {
+		a.Limit = limit
+	}
+	a.UpdatedAt = time.Now().UTC()
+	return nil
+}
 
-	if amountToPay > 0 {
-		if a.Limit+amountToPay >= a.Reversal {
-			a.Limit += amountToPay
-			a.Limit -= a.Reversal
-			a.Balance += a.Limit
-			a.Limit = a.Reversal
-			a.UpdatedAt = time.Now().UTC()
-			return nil
-		} else {
-			a.Limit += amountToPay
-			a.UpdatedAt = time.Now().UTC()
-			return nil
+// Refund gives back (part of) a payment. Payments consume the balance before
+// the credit line, so a refund restores the credit line first.
+func (a *Account) Refund(amount Money) error {
+	if err := a.checkNotClosed(); err != nil {
+		return err
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	return a.restoreCredit(amount)
+}
+
+// ReversePayment undoes a payment exactly as it was charged: fromCredit, the
+// part drawn on the credit line, goes back to it and the rest of amount to
+// the balance.
+func (a *Account) ReversePayment(amount, fromCredit Money) error {
+	if err := a.checkNotClosed(); err != nil {
+		return err
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	fromBalance, err := amount.Sub(fromCredit)
+	if err != nil {
+		return err
+	}
+	if fromCredit.IsNegative() || fromBalance.IsNegative() {
+		return fmt.Errorf("%w: %s of %s drawn on credit", ErrInvalidAmount, fromCredit, amount)
+	}
+	if fromCredit.IsPositive() {
+		if err := a.restoreCredit(fromCredit); err != nil {
+			return err
 		}
+	}
+	balance, err := a.Balance.Add(fromBalance)
+	if err != nil {
+		return err
+	}
+	a.Balance = balance
+	a.UpdatedAt = time.Now().UTC()
+	return nil
+}
 
-	} else {
-		return fmt.Errorf("%s", ErrPaymentInsufficient)
+// Credit adds amount to the balance to compensate an earlier debit.
+func (a *Account) Credit(amount Money) error {
+	if err := a.checkNotClosed(); err != nil {
+		return err
 	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	balance, err := a.Balance.Add(amount)
+	if err != nil {
+		return err
+	}
+	a.Balance = balance
+	a.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// Debit removes amount from the balance to compensate an earlier credit. It
+// fails with ErrRefundInsufficient when the money has already been spent.
+func (a *Account) Debit(amount Money) error {
+	if err := a.checkNotClosed(); err != nil {
+		return err
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	if c, _ := amount.Cmp(a.Balance); c > 0 {
+		return ErrRefundInsufficient
+	}
+	a.Balance, _ = a.Balance.Sub(amount)
+	a.UpdatedAt = time.Now().UTC()
+	return nil
 }
 
-func (a *Account) GetBalance() float64 {
-	a.Mu.Lock()
-	defer a.Mu.Unlock()
+// Hold reserves amount on the credit line for a card authorization. Like
+// Payment it fails when the currency differs or the amount is not positive,
+// but it only draws on Limit and never touches the balance.
+func (a *Account) Hold(amount Money) error {
+	if err := a.checkActive(); err != nil {
+		return err
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	if c, _ := amount.Cmp(a.Limit); c > 0 {
+		return ErrCreditLimitExceeded
+	}
+	a.Limit, _ = a.Limit.Sub(amount)
+	a.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// ReleaseHold gives back a hold that was voided, expired or only partly
+// captured. Releasing nothing, what a full capture leaves, is a no-op.
+func (a *Account) ReleaseHold(amount Money) error {
+	if err := a.checkNotClosed(); err != nil {
+		return err
+	}
+	if amount.IsZero() && amount.SameCurrency(a.Balance) {
+		return nil
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return err
+	}
+	return a.restoreCredit(amount)
+}
+
+func (a *Account) GetBalance() Money {
 	return a.Balance
 }
 
-func (a *Account) GetLimit() float64 {
-	a.Mu.Lock()
-	defer a.Mu.Unlock()
+func (a *Account) GetLimit() Money {
 	return a.Limit
 }
 
-func (a *Account) Transfer(toAcc *Account, amount float64) (*Account, error) {
-	a.Mu.Lock()
-	defer a.Mu.Unlock()
-	if amount > 0 {
-		if a.Balance >= amount {
-			a.Balance -= amount
-			toAcc.Balance += amount
-			a.UpdatedAt = time.Now().UTC()
-			toAcc.UpdatedAt = time.Now().UTC()
-			return toAcc, nil
-		} else {
-			return a, fmt.Errorf("%s", ErrTransferInsufficient)
-		}
+func (a *Account) Transfer(toAcc *Account, amount Money) (*Account, error) {
+	if err := a.checkActive(); err != nil {
+		return a, err
+	}
+	if err := toAcc.checkActive(); err != nil {
+		return a, err
+	}
+	if err := a.checkAmount(amount); err != nil {
+		return a, err
+	}
+	if err := toAcc.checkCurrency(amount); err != nil {
+		return a, err
 	}
+	if c, _ := a.Balance.Cmp(amount); c < 0 {
+		return a, ErrTransferInsufficient
+	}
+	credited, err := toAcc.Balance.Add(amount)
+	if err != nil {
+		return a, err
+	}
+	a.Balance, _ = a.Balance.Sub(amount)
+	toAcc.Balance = credited
+	a.UpdatedAt = time.Now().UTC()
+	toAcc.UpdatedAt = time.Now().UTC()
 	return toAcc, nil
 }
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func activeAccount(balance, limit int64) *Account {
+	return &Account{
+		Balance:  NewMoney(balance, "BRL"),
+		Limit:    NewMoney(limit, "BRL"),
+		Reversal: NewMoney(limit, "BRL"),
+		Status:   AccountActive,
+	}
+}
+
+func TestAccountRejectsNonPositiveAmounts(t *testing.T) {
+	ops := map[string]func(a *Account, m Money) error{
+		"Deposit":      (*Account).Deposit,
+		"Withdraw":     (*Account).Withdraw,
+		"Payment":      (*Account).Payment,
+		"PaymentLimit": (*Account).PaymentLimit,
+		"Refund":       (*Account).Refund,
+		"Credit":       (*Account).Credit,
+		"Debit":        (*Account).Debit,
+		"Hold":         (*Account).Hold,
+		"Transfer": func(a *Account, m Money) error {
+			_, err := a.Transfer(activeAccount(0, 0), m)
+			return err
+		},
+	}
+	for name, op := range ops {
+		for _, minor := range []int64{0, -500} {
+			a := activeAccount(1000, 1000)
+			err := op(a, NewMoney(minor, "BRL"))
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("%s(%d): err = %v, want ErrInvalidAmount", name, minor, err)
+			}
+			if a.Balance.MinorUnits() != 1000 || a.Limit.MinorUnits() != 1000 {
+				t.Errorf("%s(%d) changed the account: balance %s, limit %s", name, minor, a.Balance, a.Limit)
+			}
+		}
+	}
+}
+
+func TestMoneyCmpCurrencyMismatch(t *testing.T) {
+	if _, err := NewMoney(100, "BRL").Cmp(NewMoney(100, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("err = %v, want ErrCurrencyMismatch", err)
+	}
+	c, err := NewMoney(100, "BRL").Cmp(NewMoney(200, "BRL"))
+	if err != nil || c != -1 {
+		t.Fatalf("Cmp = %d, %v; want -1, nil", c, err)
+	}
+}
+
+// TestReversePaymentRestoresChargedSource reverses a payment made from the
+// balance while the credit line is in use by a later one: the money must go
+// back to the balance, leaving the credit line as it is.
+func TestReversePaymentRestoresChargedSource(t *testing.T) {
+	a := activeAccount(20000, 10000)
+	if err := a.Payment(NewMoney(10000, "BRL")); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Payment(NewMoney(15000, "BRL")); err != nil {
+		t.Fatal(err)
+	}
+	if a.Balance.MinorUnits() != 0 || a.Limit.MinorUnits() != 5000 {
+		t.Fatalf("after payments: balance %s, limit %s", a.Balance, a.Limit)
+	}
+
+	if err := a.ReversePayment(NewMoney(10000, "BRL"), NewMoney(0, "BRL")); err != nil {
+		t.Fatal(err)
+	}
+	if a.Balance.MinorUnits() != 10000 || a.Limit.MinorUnits() != 5000 {
+		t.Fatalf("after reversing the first payment: balance %s, limit %s; want 100.00 and 50.00", a.Balance, a.Limit)
+	}
+
+	if err := a.ReversePayment(NewMoney(15000, "BRL"), NewMoney(5000, "BRL")); err != nil {
+		t.Fatal(err)
+	}
+	if a.Balance.MinorUnits() != 20000 || a.Limit.MinorUnits() != 10000 {
+		t.Fatalf("after reversing both: balance %s, limit %s; want 200.00 and 100.00", a.Balance, a.Limit)
+	}
+}
+
+func TestReversePaymentRejectsCreditAboveAmount(t *testing.T) {
+	a := activeAccount(0, 10000)
+	err := a.ReversePayment(NewMoney(1000, "BRL"), NewMoney(2000, "BRL"))
+	if !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("err = %v, want ErrInvalidAmount", err)
+	}
+}
+
+// TestNewAccountStartsPending opens an account, which must refuse business
+// until it is activated.
+func TestNewAccountStartsPending(t *testing.T) {
+	product := Product{Code: "checking", Currencies: []string{"BRL"}, DefaultLimit: "0", MaxLimit: "500"}
+	a, err := NewAccount(&Customer{ID: "alice"}, product, "", NewMoney(0, "BRL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Status != AccountPending {
+		t.Fatalf("status = %s, want pending", a.Status)
+	}
+	if err := a.Deposit(NewMoney(1000, "BRL")); !errors.Is(err, ErrAccountPending) {
+		t.Fatalf("deposit on a pending account: err = %v, want ErrAccountPending", err)
+	}
+
+	if err := a.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Deposit(NewMoney(1000, "BRL")); err != nil {
+		t.Fatalf("deposit after activation: %v", err)
+	}
+	if err := a.Activate(); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("activating twice: err = %v, want ErrInvalidTransition", err)
+	}
+}
+
+// TestInsufficientFundsKeepTheirIdentity lets statusFor and the operation
+// metrics tell the shortfalls apart by their sentinels.
+func TestInsufficientFundsKeepTheirIdentity(t *testing.T) {
+	if err := activeAccount(100, 0).Withdraw(NewMoney(500, "BRL")); !errors.Is(err, ErrWithdrawalInsufficient) {
+		t.Errorf("Withdraw: err = %v, want ErrWithdrawalInsufficient", err)
+	}
+	if err := activeAccount(100, 100).Payment(NewMoney(500, "BRL")); !errors.Is(err, ErrPaymentInsufficient) {
+		t.Errorf("Payment: err = %v, want ErrPaymentInsufficient", err)
+	}
+	if _, err := activeAccount(100, 0).Transfer(activeAccount(0, 0), NewMoney(500, "BRL")); !errors.Is(err, ErrTransferInsufficient) {
+		t.Errorf("Transfer: err = %v, want ErrTransferInsufficient", err)
+	}
+}
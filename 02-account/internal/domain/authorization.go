@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type AuthorizationStatus string
+
+const (
+	AuthorizationPending  AuthorizationStatus = "pending"
+	AuthorizationCaptured AuthorizationStatus = "captured"
+	AuthorizationVoided   AuthorizationStatus = "voided"
+	AuthorizationExpired  AuthorizationStatus = "expired"
+)
+
+var (
+	ErrAuthorizationNotFound   = errors.New("authorization not found")
+	ErrAuthorizationNotPending = errors.New("authorization is no longer pending")
+	ErrCaptureExceedsHold      = errors.New("capture amount exceeds the authorized amount")
+)
+
+// Authorization is a hold placed on an account's credit line by a card
+// purchase. It stays pending until the merchant captures or voids it, or
+// until ExpiresAt, after which the hold is released automatically.
+type Authorization struct {
+	ID            string
+	CardID        string
+	AccountNumber string
+	Merchant      string
+	Amount        Money
+	Captured      Money
+	Status        AuthorizationStatus
+	TransactionID string
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func NewAuthorization(card *Card, merchant string, amount Money, ttl time.Duration) *Authorization {
+	now := time.Now().UTC()
+	return &Authorization{
+		ID:            utils.GenerateUUID(),
+		CardID:        card.ID,
+		AccountNumber: card.AccountNumber,
+		Merchant:      merchant,
+		Amount:        amount,
+		Captured:      NewMoney(0, amount.Currency()),
+		Status:        AuthorizationPending,
+		ExpiresAt:     now.Add(ttl),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Capture settles amount of the hold. A zero amount captures everything. It
+// returns the part of the hold that must be released back to the credit line.
+func (a *Authorization) Capture(amount Money, now time.Time) (Money, error) {
+	if err := a.pending(now); err != nil {
+		return Money{}, err
+	}
+	if amount.IsZero() {
+		amount = a.Amount
+	}
+	if !amount.IsPositive() {
+		return Money{}, ErrInvalidAmount
+	}
+	c, err := amount.Cmp(a.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+	if c > 0 {
+		return Money{}, ErrCaptureExceedsHold
+	}
+	rest, _ := a.Amount.Sub(amount)
+	a.Captured = amount
+	a.Status = AuthorizationCaptured
+	a.UpdatedAt = now.UTC()
+	return rest, nil
+}
+
+// Void cancels the hold, returning the amount to release.
+func (a *Authorization) Void(now time.Time) (Money, error) {
+	if err := a.pending(now); err != nil {
+		return Money{}, err
+	}
+	a.Status = AuthorizationVoided
+	a.UpdatedAt = now.UTC()
+	return a.Amount, nil
+}
+
+// Expire marks an abandoned hold as expired, returning the amount to release.
+func (a *Authorization) Expire(now time.Time) (Money, error) {
+	if a.Status != AuthorizationPending {
+		return Money{}, ErrAuthorizationNotPending
+	}
+	a.Status = AuthorizationExpired
+	a.UpdatedAt = now.UTC()
+	return a.Amount, nil
+}
+
+func (a *Authorization) pending(now time.Time) error {
+	if a.Status != AuthorizationPending || !now.Before(a.ExpiresAt) {
+		return ErrAuthorizationNotPending
+	}
+	return nil
+}
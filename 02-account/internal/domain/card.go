@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/pkg/genrand"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type CardStatus string
+
+const (
+	CardActive  CardStatus = "active"
+	CardBlocked CardStatus = "blocked"
+)
+
+// cardValidityYears is how long a newly issued card stays valid.
+const cardValidityYears = 5
+
+var (
+	ErrCardNotFound     = errors.New("card not found")
+	ErrCardBlocked      = errors.New("card is blocked")
+	ErrInvalidCardBrand = errors.New("invalid card brand")
+	ErrCardKeyMissing   = errors.New("card tokenisation key is not configured")
+	ErrInvalidPAN       = errors.New("invalid card number")
+	ErrCardDeclined     = errors.New("card declined")
+)
+
+// cardBrands maps each supported brand to the length of its CCV.
+var cardBrands = map[string]int{
+	"visa":       3,
+	"mastercard": 3,
+	"amex":       4,
+	"discover":   3,
+}
+
+// Card is a payment card linked to an account. The full PAN and CCV are never
+// stored: the PAN is kept as a keyed token plus a masked form for display,
+// and the CCV as a bcrypt hash.
+//
+// Attempts counts the verifications started since the card last verified
+// successfully, including those still running.
+type Card struct {
+	ID              string
+	AccountNumber   string
+	CustomerID      string
+	Brand           string
+	PANToken        string
+	MaskedPAN       string
+	CCVHash         string
+	ExpirationMonth int
+	ExpirationYear  int
+	Status          CardStatus
+	Attempts        int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// IssuedCard carries the card secrets in clear. It only exists in memory at
+// issuance time so they can be shown to the card holder once.
+type IssuedCard struct {
+	PAN string
+	CCV string
+}
+
+// NewCard issues a card of the given brand for acc. panKey is the secret used
+// to tokenise the PAN.
+func NewCard(acc *Account, brand string, panKey []byte) (*Card, *IssuedCard, error) {
+	brand = strings.ToLower(brand)
+	ccvLen, ok := cardBrands[brand]
+	if !ok {
+		return nil, nil, ErrInvalidCardBrand
+	}
+	if len(panKey) == 0 {
+		return nil, nil, ErrCardKeyMissing
+	}
+	if err := acc.checkActive(); err != nil {
+		return nil, nil, err
+	}
+
+	gen := genrand.GenerateCard(brand, cardValidityYears, ccvLen)
+	ccvHash, err := utils.HashSecret(gen.CCV)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	card := &Card{
+		ID:              utils.GenerateUUID(),
+		AccountNumber:   acc.AccountNumber,
+		CustomerID:      acc.CustomerID,
+		Brand:           brand,
+		PANToken:        TokenizePAN(gen.CardNumber, panKey),
+		MaskedPAN:       MaskPAN(gen.CardNumber),
+		CCVHash:         ccvHash,
+		ExpirationMonth: gen.ExpirationMonth,
+		ExpirationYear:  gen.ExpirationYear,
+		Status:          CardActive,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	return card, &IssuedCard{PAN: gen.CardNumber, CCV: gen.CCV}, nil
+}
+
+// TokenizePAN derives the stable lookup token of a card number.
+func TokenizePAN(pan string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(pan))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// MaskPAN keeps the issuer prefix and the last four digits only.
+func MaskPAN(pan string) string {
+	if len(pan) < 10 {
+		return strings.Repeat("*", len(pan))
+	}
+	return pan[:6] + strings.Repeat("*", len(pan)-10) + pan[len(pan)-4:]
+}
+
+func (c *Card) Block() {
+	c.Status = CardBlocked
+	c.UpdatedAt = time.Now().UTC()
+}
+
+func (c *Card) Unblock() {
+	c.Status = CardActive
+	c.Attempts = 0
+	c.UpdatedAt = time.Now().UTC()
+}
+
+// Expired reports whether the card is past the last day of its expiration
+// month at now.
+func (c *Card) Expired(now time.Time) bool {
+	end := time.Date(c.ExpirationYear, time.Month(c.ExpirationMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !now.UTC().Before(end)
+}
+
+// Verify checks the details a merchant presents against the stored card. The
+// order matters: an expired or blocked card is reported as such even when the
+// CCV is right, and the CCV is only compared once everything else matches.
+func (c *Card) Verify(ccv string, month, year int, now time.Time) error {
+	if c.Status == CardBlocked {
+		return ErrCardBlocked
+	}
+	if month != c.ExpirationMonth || year != c.ExpirationYear {
+		return ErrCardDeclined
+	}
+	if c.Expired(now) {
+		return ErrCreditCardExpired
+	}
+	if !utils.CheckPasswordHash(ccv, c.CCVHash) {
+		return ErrInvalidCVV
+	}
+	return nil
+}
+
+// ValidLuhn reports whether pan is all digits and passes the Luhn checksum.
+func ValidLuhn(pan string) bool {
+	if len(pan) < 12 || len(pan) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(pan) - 1; i >= 0; i-- {
+		d := int(pan[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
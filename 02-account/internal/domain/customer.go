@@ -1,26 +1,16 @@
 package domain
 
 import (
-	"time"
+	"errors"
 
-	"github.com/adilsonmenechini/golabbank/pkg/utils"
+	"github.com/adilsonmenechini/golabbank/platform/identity"
 )
 
-type Customer struct {
-	ID        string
-	Name      string
-	Email     string
-	Password  string
-	CreatedAt time.Time
-}
+var (
+	ErrCustomerNotFound     = identity.ErrCustomerNotFound
+	ErrDirectoryUnavailable = errors.New("customer directory unavailable")
+	ErrTokenRevoked         = errors.New("token has been revoked")
+)
 
-func NewCustomer(id, name, email, password string) Customer {
-	pwd := utils.HashPassword(password)
-	return Customer{
-		ID:        id,
-		Name:      name,
-		Email:     email,
-		Password:  pwd,
-		CreatedAt: time.Now().UTC(),
-	}
-}
+// Customer is the customer record shared with the customer service.
+type Customer = identity.Customer
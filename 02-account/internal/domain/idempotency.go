@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type IdempotencyStatus string
+
+const (
+	IdempotencyPending   IdempotencyStatus = "pending"
+	IdempotencyCompleted IdempotencyStatus = "completed"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// IdempotencyRecord stores the first outcome of a request made with a given
+// Idempotency-Key so that client retries can be answered without re-running it.
+type IdempotencyRecord struct {
+	CustomerID   string
+	Key          string
+	RequestHash  string
+	Status       IdempotencyStatus
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
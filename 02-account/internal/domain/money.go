@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used when a request does not name a currency.
+const DefaultCurrency = "BRL"
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrMoneyOverflow    = errors.New("amount overflow")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrInvalidCurrency  = errors.New("invalid currency")
+)
+
+// currencyExponents holds the number of minor-unit digits of each supported
+// ISO 4217 currency.
+var currencyExponents = map[string]int{
+	"BRL": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"JPY": 0,
+}
+
+// Money is an exact monetary amount held as an integer number of minor units
+// (centavos for BRL, cents for USD) together with its currency code.
+type Money struct {
+	amount   int64
+	currency string
+}
+
+func NewMoney(minorUnits int64, currency string) Money {
+	return Money{amount: minorUnits, currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "12.34" into Money. Amounts with
+// more fractional digits than the currency allows are rejected rather than
+// rounded.
+func ParseMoney(s, currency string) (Money, error) {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	currency = strings.ToUpper(currency)
+	exp, ok := currencyExponents[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > exp || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrMoneyOverflow, s)
+	}
+	if neg {
+		minor = -minor
+	}
+	return Money{amount: minor, currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) MinorUnits() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() string {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) SameCurrency(o Money) bool {
+	return m.currency == o.currency
+}
+
+// Cmp compares m with o and returns -1, 0 or +1. Amounts in different
+// currencies cannot be compared and fail with ErrCurrencyMismatch.
+func (m Money) Cmp(o Money) (int, error) {
+	if !m.SameCurrency(o) {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.amount < o.amount:
+		return -1, nil
+	case m.amount > o.amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Add returns m+o, failing on currency mismatch or int64 overflow.
+func (m Money) Add(o Money) (Money, error) {
+	if !m.SameCurrency(o) {
+		return Money{}, ErrCurrencyMismatch
+	}
+	if (o.amount > 0 && m.amount > math.MaxInt64-o.amount) ||
+		(o.amount < 0 && m.amount < math.MinInt64-o.amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{amount: m.amount + o.amount, currency: m.currency}, nil
+}
+
+// Sub returns m-o, failing on currency mismatch or int64 overflow.
+func (m Money) Sub(o Money) (Money, error) {
+	if o.amount == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+	return m.Add(Money{amount: -o.amount, currency: o.currency})
+}
+
+// String formats the amount as a plain decimal, e.g. "-12.30".
+func (m Money) String() string {
+	exp := currencyExponents[m.currency]
+	sign := ""
+	u := uint64(m.amount)
+	if m.amount < 0 {
+		sign = "-"
+		u = uint64(-(m.amount + 1)) + 1
+	}
+	digits := strconv.FormatUint(u, 10)
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// MarshalJSON encodes Money as a decimal string so clients never see a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts either a decimal string or a bare JSON number. The
+// literal text is parsed exactly; the currency keeps its current value or
+// falls back to DefaultCurrency.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(b, &n); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, b)
+		}
+		s = n.String()
+	}
+	parsed, err := ParseMoney(s, m.currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+var (
+	ErrUnknownProduct       = errors.New("unknown account product")
+	ErrInvalidProduct       = errors.New("invalid account product")
+	ErrCurrencyNotOffered   = errors.New("currency not offered by the account product")
+	ErrLimitAboveProductMax = errors.New("credit limit above the account product maximum")
+)
+
+// Product describes a kind of account the bank offers. Amounts are decimal
+// strings so a product can be offered in several currencies; they are parsed
+// in the currency of the account being opened.
+type Product struct {
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Prefix       string   `json:"prefix"`
+	Length       int      `json:"length"`
+	DefaultLimit string   `json:"default_limit"`
+	MaxLimit     string   `json:"max_limit"`
+	Currencies   []string `json:"currencies"`
+}
+
+// minRandomDigits keeps a product's number space large enough that random
+// allocation rarely collides: 10^6 numbers per prefix.
+const minRandomDigits = 6
+
+// Offers reports whether accounts of the product may hold currency.
+func (p Product) Offers(currency string) bool {
+	for _, c := range p.Currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// Limits returns the default and maximum credit limit in currency.
+func (p Product) Limits(currency string) (Money, Money, error) {
+	def, err := ParseMoney(p.DefaultLimit, currency)
+	if err != nil {
+		return Money{}, Money{}, err
+	}
+	max, err := ParseMoney(p.MaxLimit, currency)
+	if err != nil {
+		return Money{}, Money{}, err
+	}
+	return def, max, nil
+}
+
+func (p Product) validate() error {
+	if p.Code == "" || p.Prefix == "" || !isDigits(p.Prefix) {
+		return fmt.Errorf("%w %q: code and a numeric prefix are required", ErrInvalidProduct, p.Code)
+	}
+	// The prefix, the random digits and the check digit.
+	if p.Length < len(p.Prefix)+minRandomDigits+1 || p.Length > 20 {
+		return fmt.Errorf("%w %q: length %d does not fit prefix %q", ErrInvalidProduct, p.Code, p.Length, p.Prefix)
+	}
+	if len(p.Currencies) == 0 {
+		return fmt.Errorf("%w %q: no currencies", ErrInvalidProduct, p.Code)
+	}
+	for _, c := range p.Currencies {
+		def, max, err := p.Limits(c)
+		if err != nil {
+			return fmt.Errorf("%w %q: %v", ErrInvalidProduct, p.Code, err)
+		}
+		if c, err := def.Cmp(max); err != nil || def.IsNegative() || c > 0 {
+			return fmt.Errorf("%w %q: default limit above maximum", ErrInvalidProduct, p.Code)
+		}
+	}
+	return nil
+}
+
+// Catalog is the set of account products, keyed by code.
+type Catalog struct {
+	products map[string]Product
+}
+
+// NewCatalog validates products and indexes them by code.
+func NewCatalog(products []Product) (*Catalog, error) {
+	c := &Catalog{products: make(map[string]Product, len(products))}
+	for _, p := range products {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		if _, dup := c.products[p.Code]; dup {
+			return nil, fmt.Errorf("%w %q: duplicate code", ErrInvalidProduct, p.Code)
+		}
+		c.products[p.Code] = p
+	}
+	return c, nil
+}
+
+// LoadCatalog reads a JSON array of products.
+func LoadCatalog(r io.Reader) (*Catalog, error) {
+	var products []Product
+	if err := json.NewDecoder(r).Decode(&products); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProduct, err)
+	}
+	return NewCatalog(products)
+}
+
+// Lookup returns the product with the given code.
+func (c *Catalog) Lookup(code string) (Product, error) {
+	p, ok := c.products[code]
+	if !ok {
+		return Product{}, fmt.Errorf("%w: %q", ErrUnknownProduct, code)
+	}
+	return p, nil
+}
+
+// Products lists the catalog ordered by code.
+func (c *Catalog) Products() []Product {
+	items := make([]Product, 0, len(c.products))
+	for _, p := range c.products {
+		items = append(items, p)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Code < items[j].Code })
+	return items
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestShippedCatalogLoads(t *testing.T) {
+	f, err := os.Open("../../config/products.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	c, err := LoadCatalog(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range c.Products() {
+		if random := p.Length - len(p.Prefix) - 1; random < minRandomDigits {
+			t.Errorf("product %s leaves %d random digits", p.Code, random)
+		}
+	}
+}
+
+func TestProductNeedsRandomDigits(t *testing.T) {
+	p := Product{Code: "short", Prefix: "123", Length: 7, DefaultLimit: "0", MaxLimit: "0", Currencies: []string{"BRL"}}
+	if _, err := NewCatalog([]Product{p}); !errors.Is(err, ErrInvalidProduct) {
+		t.Fatalf("3 random digits: err = %v, want ErrInvalidProduct", err)
+	}
+	p.Length = 10
+	if _, err := NewCatalog([]Product{p}); err != nil {
+		t.Fatalf("6 random digits: %v", err)
+	}
+}
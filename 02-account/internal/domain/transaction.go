@@ -1,31 +1,117 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
 
 type TransactionType string
 type Success int
 
 const (
-	Deposit  TransactionType = "Deposit"
-	Transfer TransactionType = "Transfer"
-	Withdraw TransactionType = "Withdraw"
-	Payment  TransactionType = "Payment"
-	Refund   TransactionType = "Refund"
-	Reversal TransactionType = "Reversal"
+	Deposit      TransactionType = "Deposit"
+	Transfer     TransactionType = "Transfer"
+	Withdraw     TransactionType = "Withdraw"
+	Payment      TransactionType = "Payment"
+	PaymentLimit TransactionType = "PaymentLimit"
+	Refund       TransactionType = "Refund"
+	Reversal     TransactionType = "Reversal"
 )
 
 // Custom error types
 var (
-	ErrInsufficientFunds      = errors.New("insufficient funds")
-	ErrDepositLimitExceeded   = errors.New("deposit limit exceeded")
-	ErrCreditLimitExceeded    = errors.New("credit card limit exceeded")
-	ErrCreditCardExpired      = errors.New("credit card expired")
-	ErrDebitInsufficient      = errors.New("debit failed - insufficient funds")
-	ErrPaymentInsufficient    = errors.New("insufficient funds for payment")
-	ErrPaymentLimitExceeded   = errors.New("payment limit exceeded")
-	ErrRefundInsufficient     = errors.New("refund failed - insufficient funds")
-	ErrWithdrawalInsufficient = errors.New("withdrawal failed - insufficient funds")
-	ErrTransferInsufficient   = errors.New("transfer failed - insufficient funds")
-	ErrTransactionNotFound    = errors.New("transaction not found")
-	ErrInvalidCVV             = errors.New("invalid CCV")
+	ErrInsufficientFunds        = errors.New("insufficient funds")
+	ErrDepositLimitExceeded     = errors.New("deposit limit exceeded")
+	ErrCreditLimitExceeded      = errors.New("credit card limit exceeded")
+	ErrCreditCardExpired        = errors.New("credit card expired")
+	ErrDebitInsufficient        = errors.New("debit failed - insufficient funds")
+	ErrPaymentInsufficient      = errors.New("insufficient funds for payment")
+	ErrPaymentLimitExceeded     = errors.New("payment limit exceeded")
+	ErrRefundInsufficient       = errors.New("refund failed - insufficient funds")
+	ErrWithdrawalInsufficient   = errors.New("withdrawal failed - insufficient funds")
+	ErrTransferInsufficient     = errors.New("transfer failed - insufficient funds")
+	ErrTransferSameAccount      = errors.New("transfer failed - source and destination accounts are the same")
+	ErrTransactionNotFound      = errors.New("transaction not found")
+	ErrTransactionReversed      = errors.New("transaction already reversed")
+	ErrTransactionRefunded      = errors.New("transaction has refunds and cannot be reversed")
+	ErrTransactionNotReversible = errors.New("transaction type cannot be reversed")
+	ErrTransactionNotRefundable = errors.New("only payments can be refunded")
+	ErrInvalidCVV               = errors.New("invalid CCV")
 )
+
+// Transaction is an immutable ledger entry. Every balance mutation on an
+// account produces exactly one Transaction; a transfer produces one per
+// account, both sharing the same OperationID. Refunds and reversals point at
+// the entry they compensate through ReferenceID.
+//
+// CreditAmount is the part of Amount drawn on the credit line; the rest moved
+// the balance. It is zero for entries that did not draw on credit.
+type Transaction struct {
+	ID            string
+	OperationID   string
+	ReferenceID   string
+	AccountNumber string
+	Type          TransactionType
+	Amount        Money
+	BalanceBefore Money
+	BalanceAfter  Money
+	CreditAmount  Money
+	Counterparty  string
+	CreatedAt     time.Time
+}
+
+func NewTransaction(operationID, accountNumber string, tType TransactionType, amount, before, after Money, counterparty string) Transaction {
+	return Transaction{
+		ID:            utils.GenerateUUID(),
+		OperationID:   operationID,
+		AccountNumber: accountNumber,
+		Type:          tType,
+		Amount:        amount,
+		BalanceBefore: before,
+		BalanceAfter:  after,
+		CreditAmount:  NewMoney(0, amount.Currency()),
+		Counterparty:  counterparty,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+// IsDebit reports whether the entry took money out of the account.
+func (t Transaction) IsDebit() bool {
+	switch t.Type {
+	case Withdraw, Payment:
+		return true
+	case Transfer, Reversal:
+		c, err := t.BalanceAfter.Cmp(t.BalanceBefore)
+		return err == nil && c < 0
+	}
+	return false
+}
+
+// Reversible reports whether the entry can be undone by a Reversal.
+func (t Transaction) Reversible() bool {
+	switch t.Type {
+	case Deposit, Withdraw, Transfer, Payment:
+		return true
+	}
+	return false
+}
+
+// StatementCursor marks the last entry of a statement page. Entries are
+// ordered by (CreatedAt, ID), so the next page starts strictly after it.
+type StatementCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// StatementFilter selects ledger entries of one account. Zero values disable
+// the corresponding filter.
+type StatementFilter struct {
+	AccountNumber string
+	From          time.Time
+	To            time.Time
+	Types         []TransactionType
+	After         *StatementCursor
+	Limit         int
+}
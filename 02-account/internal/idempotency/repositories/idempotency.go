@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+type (
+	IdempotencyRepository interface {
+		// Reserve claims key for a new request. It returns (nil, nil) when the
+		// caller now owns the key, or the stored record when the key is live.
+		Reserve(ctx context.Context, customerID, key, requestHash string, ttl time.Duration) (*domain.IdempotencyRecord, error)
+		Complete(ctx context.Context, customerID, key string, statusCode int, body []byte) error
+		Release(ctx context.Context, customerID, key string) error
+		DeleteExpired(ctx context.Context) (int64, error)
+	}
+
+	idempotencyRepository struct {
+		logger *utils.Logger
+		db     *sql.DB
+	}
+)
+
+// Reserve implements IdempotencyRepository. A live key can be released
+// between the insert and the read that follows it; the insert is then tried
+// again, since the key is free.
+func (ir *idempotencyRepository) Reserve(ctx context.Context, customerID, key, requestHash string, ttl time.Duration) (*domain.IdempotencyRecord, error) {
+	for attempt := 1; ; attempt++ {
+		rec, err := ir.reserve(ctx, customerID, key, requestHash, ttl)
+		if errors.Is(err, sql.ErrNoRows) && attempt < reserveAttempts {
+			continue
+		}
+		return rec, err
+	}
+}
+
+func (ir *idempotencyRepository) reserve(ctx context.Context, customerID, key, requestHash string, ttl time.Duration) (*domain.IdempotencyRecord, error) {
+	now := time.Now().UTC()
+
+	res, err := ir.db.ExecContext(ctx, reserveKey,
+		customerID,
+		key,
+		requestHash,
+		domain.IdempotencyPending,
+		now,
+		now.Add(ttl),
+	)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 1 {
+		return nil, nil
+	}
+
+	var i domain.IdempotencyRecord
+	row := ir.db.QueryRowContext(ctx, getKey, customerID, key)
+	err = row.Scan(
+		&i.CustomerID,
+		&i.Key,
+		&i.RequestHash,
+		&i.Status,
+		&i.StatusCode,
+		&i.ResponseBody,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// Complete implements IdempotencyRepository.
+func (ir *idempotencyRepository) Complete(ctx context.Context, customerID, key string, statusCode int, body []byte) error {
+	_, err := ir.db.ExecContext(ctx, completeKey, customerID, key, domain.IdempotencyCompleted, statusCode, body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Release implements IdempotencyRepository.
+func (ir *idempotencyRepository) Release(ctx context.Context, customerID, key string) error {
+	_, err := ir.db.ExecContext(ctx, releaseKey, customerID, key, domain.IdempotencyPending)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired implements IdempotencyRepository.
+func (ir *idempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := ir.db.ExecContext(ctx, deleteExpiredKeys, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func NewIdempotencyRepository(DB *sql.DB) IdempotencyRepository {
+	return &idempotencyRepository{
+		logger: utils.NewLogger("IdempotencyRepository"),
+		db:     DB,
+	}
+}
+
+// reserveAttempts bounds how often Reserve retries a key that vanished
+// under it; each retry means another request released the key meanwhile.
+const reserveAttempts = 3
+
+const (
+	// reserveKey inserts a pending row, or takes over a row whose TTL has
+	// elapsed. It affects no rows while a live record exists.
+	reserveKey = `INSERT INTO idempotency_keys (customer_id, key, request_hash, status, created_at, expires_at) VALUES ( $1, $2, $3, $4, $5, $6)
+		ON CONFLICT (customer_id, key) DO UPDATE SET request_hash = EXCLUDED.request_hash, status = EXCLUDED.status, status_code = 0, response_body = NULL, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < EXCLUDED.created_at`
+	getKey            = `SELECT customer_id, key, request_hash, status, status_code, response_body, created_at, expires_at FROM idempotency_keys WHERE customer_id = $1 AND key = $2`
+	completeKey       = `UPDATE idempotency_keys set status = $3, status_code = $4, response_body = $5 WHERE customer_id = $1 AND key = $2`
+	releaseKey        = `DELETE FROM idempotency_keys WHERE customer_id = $1 AND key = $2 AND status = $3`
+	deleteExpiredKeys = `DELETE FROM idempotency_keys WHERE expires_at < $1`
+)
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+)
+
+// TestReserveRetriesAVanishedKey has the key released between the
+// conflicting insert and the read of the live record: Reserve must claim the
+// key rather than fail with sql.ErrNoRows.
+func TestReserveRetriesAVanishedKey(t *testing.T) {
+	conn := &scriptedConn{inserted: []int64{0, 1}}
+	repo := NewIdempotencyRepository(sql.OpenDB(conn))
+
+	rec, err := repo.Reserve(context.Background(), "alice", "key-1", "hash", time.Hour)
+	if err != nil || rec != nil {
+		t.Fatalf("Reserve = %+v, %v; want the key claimed", rec, err)
+	}
+	if conn.inserts != 2 {
+		t.Fatalf("%d inserts, want the insert retried once", conn.inserts)
+	}
+}
+
+func TestReserveReturnsTheLiveRecord(t *testing.T) {
+	conn := &scriptedConn{inserted: []int64{0}, live: true}
+	repo := NewIdempotencyRepository(sql.OpenDB(conn))
+
+	rec, err := repo.Reserve(context.Background(), "alice", "key-1", "hash", time.Hour)
+	if err != nil || rec == nil || rec.Status != domain.IdempotencyCompleted {
+		t.Fatalf("Reserve = %+v, %v; want the completed record", rec, err)
+	}
+}
+
+// scriptedConn answers reserveKey with the rows affected listed in inserted,
+// one per call, and getKey with a completed record when live is set and no
+// row otherwise.
+type scriptedConn struct {
+	inserted []int64
+	inserts  int
+	live     bool
+}
+
+func (c *scriptedConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *scriptedConn) Driver() driver.Driver                        { return nil }
+
+func (c *scriptedConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *scriptedConn) Close() error                        { return nil }
+func (c *scriptedConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (c *scriptedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	n := c.inserted[c.inserts]
+	c.inserts++
+	return driver.RowsAffected(n), nil
+}
+
+func (c *scriptedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows := &recordRows{}
+	if c.live {
+		now := time.Now()
+		rows.values = [][]driver.Value{{"alice", "key-1", "hash", string(domain.IdempotencyCompleted), int64(201), []byte("{}"), now, now.Add(time.Hour)}}
+	}
+	return rows, nil
+}
+
+type recordRows struct {
+	values [][]driver.Value
+}
+
+func (r *recordRows) Columns() []string {
+	return []string{"customer_id", "key", "request_hash", "status", "status_code", "response_body", "created_at", "expires_at"}
+}
+
+func (r *recordRows) Close() error { return nil }
+
+func (r *recordRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
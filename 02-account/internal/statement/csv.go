@@ -0,0 +1,45 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+)
+
+type csvRenderer struct {
+	w *csv.Writer
+}
+
+func newCSVRenderer(w io.Writer) *csvRenderer {
+	return &csvRenderer{w: csv.NewWriter(w)}
+}
+
+func (c *csvRenderer) begin(h Header) error {
+	return c.w.Write([]string{"date", "id", "type", "amount", "balance", "currency", "counterparty"})
+}
+
+func (c *csvRenderer) line(tx domain.Transaction) error {
+	err := c.w.Write([]string{
+		tx.CreatedAt.UTC().Format(time.RFC3339),
+		tx.ID,
+		string(tx.Type),
+		signed(tx).String(),
+		tx.BalanceAfter.String(),
+		tx.Amount.Currency(),
+		tx.Counterparty,
+	})
+	if err != nil {
+		return err
+	}
+	// csv.Writer buffers internally; flushing per line keeps the buffer
+	// bounded and lets the client start receiving data immediately.
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvRenderer) end(closing domain.Money) error {
+	c.w.Flush()
+	return c.w.Error()
+}
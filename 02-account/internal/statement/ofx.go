@@ -0,0 +1,137 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+)
+
+const (
+	ofxBankID     = "LABGOBANK"
+	ofxTimeLayout = "20060102150405"
+)
+
+// ofxRenderer writes an OFX 2.2 (XML) bank statement response.
+type ofxRenderer struct {
+	w    io.Writer
+	err  error
+	asOf time.Time
+}
+
+func newOFXRenderer(w io.Writer) *ofxRenderer {
+	return &ofxRenderer{w: w}
+}
+
+func (o *ofxRenderer) printf(format string, args ...any) {
+	if o.err != nil {
+		return
+	}
+	_, o.err = fmt.Fprintf(o.w, format, args...)
+}
+
+// elem writes <tag>value</tag> with value XML-escaped.
+func (o *ofxRenderer) elem(indent int, tag, value string) {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	o.printf("%s<%s>%s</%s>\n", strings.Repeat("  ", indent), tag, b.String(), tag)
+}
+
+func (o *ofxRenderer) open(indent int, tag string) {
+	o.printf("%s<%s>\n", strings.Repeat("  ", indent), tag)
+}
+
+func (o *ofxRenderer) close(indent int, tag string) {
+	o.printf("%s</%s>\n", strings.Repeat("  ", indent), tag)
+}
+
+func (o *ofxRenderer) status(indent int) {
+	o.open(indent, "STATUS")
+	o.elem(indent+1, "CODE", "0")
+	o.elem(indent+1, "SEVERITY", "INFO")
+	o.close(indent, "STATUS")
+}
+
+func (o *ofxRenderer) begin(h Header) error {
+	o.asOf = h.GeneratedAt
+	start, end := h.From, h.To
+	if start.IsZero() {
+		start = h.OpenedAt
+	}
+	if end.IsZero() {
+		end = h.GeneratedAt
+	}
+
+	o.printf("<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n")
+	o.printf("<?OFX OFXHEADER=\"200\" VERSION=\"220\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n")
+	o.open(0, "OFX")
+	o.open(1, "SIGNONMSGSRSV1")
+	o.open(2, "SONRS")
+	o.status(3)
+	o.elem(3, "DTSERVER", h.GeneratedAt.Format(ofxTimeLayout))
+	o.elem(3, "LANGUAGE", "POR")
+	o.close(2, "SONRS")
+	o.close(1, "SIGNONMSGSRSV1")
+	o.open(1, "BANKMSGSRSV1")
+	o.open(2, "STMTTRNRS")
+	o.elem(3, "TRNUID", "0")
+	o.status(3)
+	o.open(3, "STMTRS")
+	o.elem(4, "CURDEF", h.Currency)
+	o.open(4, "BANKACCTFROM")
+	o.elem(5, "BANKID", ofxBankID)
+	o.elem(5, "ACCTID", h.AccountNumber)
+	o.elem(5, "ACCTTYPE", "CHECKING")
+	o.close(4, "BANKACCTFROM")
+	o.open(4, "BANKTRANLIST")
+	o.elem(5, "DTSTART", start.UTC().Format(ofxTimeLayout))
+	o.elem(5, "DTEND", end.UTC().Format(ofxTimeLayout))
+	return o.err
+}
+
+func (o *ofxRenderer) line(tx domain.Transaction) error {
+	o.open(5, "STMTTRN")
+	o.elem(6, "TRNTYPE", ofxType(tx))
+	o.elem(6, "DTPOSTED", tx.CreatedAt.UTC().Format(ofxTimeLayout))
+	o.elem(6, "TRNAMT", signed(tx).String())
+	o.elem(6, "FITID", tx.ID)
+	o.elem(6, "NAME", string(tx.Type))
+	if tx.Counterparty != "" {
+		o.elem(6, "MEMO", tx.Counterparty)
+	}
+	o.close(5, "STMTTRN")
+	return o.err
+}
+
+func (o *ofxRenderer) end(closing domain.Money) error {
+	o.close(4, "BANKTRANLIST")
+	o.open(4, "LEDGERBAL")
+	o.elem(5, "BALAMT", closing.String())
+	o.elem(5, "DTASOF", o.asOf.Format(ofxTimeLayout))
+	o.close(4, "LEDGERBAL")
+	o.close(3, "STMTRS")
+	o.close(2, "STMTTRNRS")
+	o.close(1, "BANKMSGSRSV1")
+	o.close(0, "OFX")
+	return o.err
+}
+
+func ofxType(tx domain.Transaction) string {
+	switch tx.Type {
+	case domain.Deposit:
+		return "DEP"
+	case domain.Withdraw:
+		return "ATM"
+	case domain.Transfer:
+		return "XFER"
+	case domain.Payment:
+		return "PAYMENT"
+	}
+	if tx.IsDebit() {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
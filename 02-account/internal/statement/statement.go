@@ -0,0 +1,155 @@
+// Package statement renders the ledger of one account as a downloadable
+// statement. Entries are written as they are read from the Source, so the
+// size of the history does not affect memory use, and the same input always
+// produces byte-identical output.
+package statement
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	OFX  Format = "ofx"
+	Text Format = "txt"
+)
+
+var ErrUnknownFormat = errors.New("unknown statement format")
+
+// ParseFormat validates a user supplied format name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case CSV, OFX, Text:
+		return f, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// ContentType is the MIME type of the rendered statement.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case OFX:
+		return "application/x-ofx"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Source provides the ledger entries of an account.
+type Source interface {
+	OpeningBalance(ctx context.Context, accountNumber string, from time.Time) (domain.Money, bool, error)
+	StreamTransactions(ctx context.Context, filter domain.StatementFilter, fn func(tx domain.Transaction) error) error
+}
+
+// Header describes the statement being rendered. GeneratedAt is supplied by
+// the caller rather than read from the clock to keep output deterministic.
+type Header struct {
+	AccountNumber  string
+	AccountType    string
+	Name           string
+	Currency       string
+	OpenedAt       time.Time
+	From           time.Time
+	To             time.Time
+	OpeningBalance domain.Money
+	GeneratedAt    time.Time
+}
+
+// Request selects what to export.
+type Request struct {
+	Format        Format
+	AccountNumber string
+	AccountType   string
+	Name          string
+	Currency      string
+	OpenedAt      time.Time
+	From          time.Time
+	To            time.Time
+	GeneratedAt   time.Time
+	// Balance is used as opening balance when the ledger holds no entry for
+	// the account at all.
+	Balance domain.Money
+}
+
+// renderer receives the statement in order: the header once, every entry,
+// then the closing balance.
+type renderer interface {
+	begin(h Header) error
+	line(tx domain.Transaction) error
+	end(closing domain.Money) error
+}
+
+func newRenderer(f Format, w io.Writer) (renderer, error) {
+	switch f {
+	case CSV:
+		return newCSVRenderer(w), nil
+	case OFX:
+		return newOFXRenderer(w), nil
+	case Text:
+		return newTextRenderer(w), nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// Export writes the statement described by req to w.
+func Export(ctx context.Context, src Source, req Request, w io.Writer) error {
+	r, err := newRenderer(req.Format, w)
+	if err != nil {
+		return err
+	}
+
+	opening, ok, err := src.OpeningBalance(ctx, req.AccountNumber, req.From)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		opening = req.Balance
+	}
+
+	h := Header{
+		AccountNumber:  req.AccountNumber,
+		AccountType:    req.AccountType,
+		Name:           req.Name,
+		Currency:       req.Currency,
+		OpenedAt:       req.OpenedAt,
+		From:           req.From,
+		To:             req.To,
+		OpeningBalance: opening,
+		GeneratedAt:    req.GeneratedAt.UTC(),
+	}
+	if err := r.begin(h); err != nil {
+		return err
+	}
+
+	closing := opening
+	filter := domain.StatementFilter{
+		AccountNumber: req.AccountNumber,
+		From:          req.From,
+		To:            req.To,
+	}
+	err = src.StreamTransactions(ctx, filter, func(tx domain.Transaction) error {
+		closing = tx.BalanceAfter
+		return r.line(tx)
+	})
+	if err != nil {
+		return err
+	}
+	return r.end(closing)
+}
+
+// signed returns the entry amount negated for debits.
+func signed(tx domain.Transaction) domain.Money {
+	if tx.IsDebit() {
+		return domain.NewMoney(-tx.Amount.MinorUnits(), tx.Amount.Currency())
+	}
+	return tx.Amount
+}
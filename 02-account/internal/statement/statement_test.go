@@ -0,0 +1,123 @@
+package statement
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// fakeSource serves a fixed ledger.
+type fakeSource struct {
+	opening domain.Money
+	hasOpen bool
+	txs     []domain.Transaction
+}
+
+func (s *fakeSource) OpeningBalance(ctx context.Context, accountNumber string, from time.Time) (domain.Money, bool, error) {
+	return s.opening, s.hasOpen, nil
+}
+
+func (s *fakeSource) StreamTransactions(ctx context.Context, filter domain.StatementFilter, fn func(tx domain.Transaction) error) error {
+	for _, tx := range s.txs {
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func brl(minor int64) domain.Money {
+	return domain.NewMoney(minor, "BRL")
+}
+
+func entry(id string, tType domain.TransactionType, amount, before, after int64, counterparty string, at time.Time) domain.Transaction {
+	return domain.Transaction{
+		ID:            id,
+		OperationID:   "op-" + id,
+		AccountNumber: "0012345",
+		Type:          tType,
+		Amount:        brl(amount),
+		BalanceBefore: brl(before),
+		BalanceAfter:  brl(after),
+		Counterparty:  counterparty,
+		CreatedAt:     at,
+	}
+}
+
+func sampleLedger() *fakeSource {
+	day := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	return &fakeSource{
+		opening: brl(10000),
+		hasOpen: true,
+		txs: []domain.Transaction{
+			entry("tx-1", domain.Deposit, 25050, 10000, 35050, "", day),
+			entry("tx-2", domain.Withdraw, 5000, 35050, 30050, "", day.Add(26*time.Hour)),
+			entry("tx-3", domain.Transfer, 12345, 30050, 17705, "0067890", day.Add(50*time.Hour)),
+			entry("tx-4", domain.Payment, 7705, 17705, 10000, "", day.Add(75*time.Hour)),
+			entry("tx-5", domain.Reversal, 7705, 10000, 17705, "", day.Add(76*time.Hour)),
+		},
+	}
+}
+
+func sampleRequest(f Format) Request {
+	return Request{
+		Format:        f,
+		AccountNumber: "0012345",
+		AccountType:   "checking",
+		Name:          "Maria & João <Silva>",
+		Currency:      "BRL",
+		OpenedAt:      time.Date(2023, 11, 20, 14, 0, 0, 0, time.UTC),
+		From:          time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC),
+		GeneratedAt:   time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestExportGolden(t *testing.T) {
+	for _, f := range []Format{CSV, OFX, Text} {
+		t.Run(string(f), func(t *testing.T) {
+			var got bytes.Buffer
+			if err := Export(context.Background(), sampleLedger(), sampleRequest(f), &got); err != nil {
+				t.Fatal(err)
+			}
+			assertGolden(t, "statement."+string(f), got.Bytes())
+		})
+	}
+}
+
+// TestExportEmptyLedger falls back to the account balance as opening balance
+// when the ledger has no entry before the period.
+func TestExportEmptyLedger(t *testing.T) {
+	req := sampleRequest(CSV)
+	req.Balance = brl(4200)
+	var got bytes.Buffer
+	if err := Export(context.Background(), &fakeSource{}, req, &got); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "empty.csv", got.Bytes())
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file:\n--- got\n%s\n--- want\n%s", name, got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/account/internal/domain"
+)
+
+const (
+	textWidth = 80
+	dateCol   = 10
+	typeCol   = 14
+	descCol   = 18
+	amountCol = 16
+	balCol    = 16
+)
+
+// textRenderer writes a fixed-width, 80 column statement.
+type textRenderer struct {
+	w   io.Writer
+	err error
+}
+
+func newTextRenderer(w io.Writer) *textRenderer {
+	return &textRenderer{w: w}
+}
+
+func (t *textRenderer) printf(format string, args ...any) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintf(t.w, format, args...)
+}
+
+func (t *textRenderer) rule() {
+	t.printf("%s\n", strings.Repeat("-", textWidth))
+}
+
+func (t *textRenderer) row(date, kind, desc, amount, balance string) {
+	t.printf("%-*s  %-*s  %-*s %*s %*s\n",
+		dateCol, clip(date, dateCol),
+		typeCol, clip(kind, typeCol),
+		descCol, clip(desc, descCol),
+		amountCol, amount,
+		balCol, balance,
+	)
+}
+
+func (t *textRenderer) begin(h Header) error {
+	t.printf("ACCOUNT STATEMENT\n")
+	t.printf("Account:   %s (%s)\n", h.AccountNumber, h.AccountType)
+	t.printf("Holder:    %s\n", h.Name)
+	t.printf("Currency:  %s\n", h.Currency)
+	t.printf("Period:    %s to %s\n", formatBound(h.From), formatBound(h.To))
+	t.printf("Generated: %s\n", h.GeneratedAt.Format(time.RFC3339))
+	t.rule()
+	t.row("DATE", "TYPE", "COUNTERPARTY", "AMOUNT", "BALANCE")
+	t.rule()
+	t.row("", "", "OPENING BALANCE", "", h.OpeningBalance.String())
+	return t.err
+}
+
+func (t *textRenderer) line(tx domain.Transaction) error {
+	t.row(
+		tx.CreatedAt.UTC().Format(time.DateOnly),
+		string(tx.Type),
+		tx.Counterparty,
+		signed(tx).String(),
+		tx.BalanceAfter.String(),
+	)
+	return t.err
+}
+
+func (t *textRenderer) end(closing domain.Money) error {
+	t.row("", "", "CLOSING BALANCE", "", closing.String())
+	t.rule()
+	return t.err
+}
+
+func formatBound(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.DateOnly)
+}
+
+func clip(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
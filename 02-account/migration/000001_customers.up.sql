@@ -1,7 +0,0 @@
-CREATE TABLE IF NOT EXISTS "customers" (
-  "id" VARCHAR(255) PRIMARY KEY,
-  "name" VARCHAR(255) NOT NULL,
-  "email" VARCHAR(255) NOT NULL,
-  "password" VARCHAR(255) NOT NULL,
-  "created_at" TIMESTAMP NOT NULL
-);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS "transactions_no_update" ON "transactions";
+DROP FUNCTION IF EXISTS transactions_immutable();
+DROP TABLE IF EXISTS "transactions";
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS "transactions" (
+  "id" VARCHAR(255) PRIMARY KEY,
+  "operation_id" VARCHAR(255) NOT NULL,
+  "account_number" VARCHAR(255) NOT NULL REFERENCES "accounts" ("account_number"),
+  "type" VARCHAR(32) NOT NULL,
+  "amount" FLOAT NOT NULL,
+  "balance_before" FLOAT NOT NULL,
+  "balance_after" FLOAT NOT NULL,
+  "counterparty" VARCHAR(255) NOT NULL DEFAULT '',
+  "created_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "transactions_account_created_idx" ON "transactions" ("account_number", "created_at");
+CREATE INDEX IF NOT EXISTS "transactions_operation_idx" ON "transactions" ("operation_id");
+
+-- The ledger is append-only: rows can be inserted but never changed.
+CREATE OR REPLACE FUNCTION transactions_immutable() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'transactions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "transactions_no_update"
+  BEFORE UPDATE OR DELETE ON "transactions"
+  FOR EACH ROW EXECUTE FUNCTION transactions_immutable();
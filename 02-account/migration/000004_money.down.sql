@@ -0,0 +1,11 @@
+ALTER TABLE "transactions"
+  ALTER COLUMN "amount" TYPE FLOAT USING "amount"::FLOAT / 100,
+  ALTER COLUMN "balance_before" TYPE FLOAT USING "balance_before"::FLOAT / 100,
+  ALTER COLUMN "balance_after" TYPE FLOAT USING "balance_after"::FLOAT / 100;
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "currency";
+
+ALTER TABLE "accounts"
+  ALTER COLUMN "balance" TYPE FLOAT USING "balance"::FLOAT / 100,
+  ALTER COLUMN "acc_limit" TYPE FLOAT USING "acc_limit"::FLOAT / 100,
+  ALTER COLUMN "acc_reversal" TYPE FLOAT USING "acc_reversal"::FLOAT / 100;
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "currency";
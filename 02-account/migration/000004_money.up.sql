@@ -0,0 +1,13 @@
+-- Money is stored as BIGINT minor units (centavos) next to an ISO 4217 code.
+-- Existing FLOAT amounts are rounded to the nearest centavo.
+ALTER TABLE "accounts" ADD COLUMN IF NOT EXISTS "currency" CHAR(3) NOT NULL DEFAULT 'BRL';
+ALTER TABLE "accounts"
+  ALTER COLUMN "balance" TYPE BIGINT USING ROUND("balance"::NUMERIC * 100)::BIGINT,
+  ALTER COLUMN "acc_limit" TYPE BIGINT USING ROUND("acc_limit"::NUMERIC * 100)::BIGINT,
+  ALTER COLUMN "acc_reversal" TYPE BIGINT USING ROUND("acc_reversal"::NUMERIC * 100)::BIGINT;
+
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "currency" CHAR(3) NOT NULL DEFAULT 'BRL';
+ALTER TABLE "transactions"
+  ALTER COLUMN "amount" TYPE BIGINT USING ROUND("amount"::NUMERIC * 100)::BIGINT,
+  ALTER COLUMN "balance_before" TYPE BIGINT USING ROUND("balance_before"::NUMERIC * 100)::BIGINT,
+  ALTER COLUMN "balance_after" TYPE BIGINT USING ROUND("balance_after"::NUMERIC * 100)::BIGINT;
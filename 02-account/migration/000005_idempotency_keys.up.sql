@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+  "customer_id" VARCHAR(255) NOT NULL,
+  "key" VARCHAR(255) NOT NULL,
+  "request_hash" VARCHAR(64) NOT NULL,
+  "status" VARCHAR(16) NOT NULL,
+  "status_code" INTEGER NOT NULL DEFAULT 0,
+  "response_body" BYTEA,
+  "created_at" TIMESTAMP NOT NULL,
+  "expires_at" TIMESTAMP NOT NULL,
+  PRIMARY KEY ("customer_id", "key")
+);
+
+CREATE INDEX IF NOT EXISTS "idempotency_keys_expires_idx" ON "idempotency_keys" ("expires_at");
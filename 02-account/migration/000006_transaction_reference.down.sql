@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "transactions_reversed_once_idx";
+DROP INDEX IF EXISTS "transactions_reference_idx";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "reference_id";
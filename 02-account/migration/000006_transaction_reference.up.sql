@@ -0,0 +1,5 @@
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "reference_id" VARCHAR(255) REFERENCES "transactions" ("id");
+
+CREATE INDEX IF NOT EXISTS "transactions_reference_idx" ON "transactions" ("reference_id");
+-- A ledger entry can be reversed at most once.
+CREATE UNIQUE INDEX IF NOT EXISTS "transactions_reversed_once_idx" ON "transactions" ("reference_id") WHERE "type" = 'Reversal';
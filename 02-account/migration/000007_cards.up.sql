@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS "cards" (
+  "id" VARCHAR(255) PRIMARY KEY,
+  "account_number" VARCHAR(255) NOT NULL REFERENCES "accounts" ("account_number"),
+  "customer_id" VARCHAR(255) NOT NULL,
+  "brand" VARCHAR(32) NOT NULL,
+  "pan_token" VARCHAR(64) NOT NULL UNIQUE,
+  "masked_pan" VARCHAR(32) NOT NULL,
+  "ccv_hash" VARCHAR(255) NOT NULL,
+  "expiration_month" INTEGER NOT NULL,
+  "expiration_year" INTEGER NOT NULL,
+  "status" VARCHAR(16) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "cards_account_idx" ON "cards" ("account_number");
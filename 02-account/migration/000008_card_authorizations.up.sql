@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS "card_authorizations" (
+  "id" VARCHAR(255) PRIMARY KEY,
+  "card_id" VARCHAR(255) NOT NULL REFERENCES "cards" ("id"),
+  "account_number" VARCHAR(255) NOT NULL REFERENCES "accounts" ("account_number"),
+  "merchant" VARCHAR(255) NOT NULL,
+  "amount" BIGINT NOT NULL,
+  "captured" BIGINT NOT NULL DEFAULT 0,
+  "currency" CHAR(3) NOT NULL,
+  "status" VARCHAR(16) NOT NULL,
+  "transaction_id" VARCHAR(255) REFERENCES "transactions" ("id"),
+  "expires_at" TIMESTAMP NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "card_authorizations_card_idx" ON "card_authorizations" ("card_id");
+CREATE INDEX IF NOT EXISTS "card_authorizations_pending_idx" ON "card_authorizations" ("expires_at") WHERE "status" = 'pending';
@@ -0,0 +1,2 @@
+ALTER TABLE "accounts" ADD COLUMN IF NOT EXISTS "status" VARCHAR(16) NOT NULL DEFAULT 'active';
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_status_check" CHECK ("status" IN ('pending', 'active', 'frozen', 'closed'));
@@ -0,0 +1,10 @@
+-- The part of an entry drawn on the credit line, in the entry's minor units.
+-- A reversal gives exactly that part back to the line and the rest to the
+-- balance. For existing payments it is whatever the balance did not cover.
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "credit_amount" BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE "transactions" DISABLE TRIGGER "transactions_no_update";
+UPDATE "transactions"
+  SET "credit_amount" = "amount" - ("balance_before" - "balance_after")
+  WHERE "type" = 'Payment' AND "amount" > "balance_before" - "balance_after";
+ALTER TABLE "transactions" ENABLE TRIGGER "transactions_no_update";
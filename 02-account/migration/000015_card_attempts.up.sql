@@ -0,0 +1,3 @@
+-- Verification attempts since the card last verified successfully. Too many
+-- failed ones block the card.
+ALTER TABLE "cards" ADD COLUMN IF NOT EXISTS "attempts" INTEGER NOT NULL DEFAULT 0;
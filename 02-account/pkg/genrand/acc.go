@@ -9,45 +9,34 @@ type generateAcoount struct {
 	AccountType cardType
 }
 
-// generateCard gera um cartão de crédito.
-func GenerateAcoount(ctype string) *generateAcoount {
+// GenerateAcoount gera um número de conta de length dígitos começando por
+// prefix, terminando com um dígito verificador Luhn.
+func GenerateAcoount(ctype, prefix string, length int) *generateAcoount {
 	return &generateAcoount{
-		CardNumber:  generateAccountNumber(ctype),
+		CardNumber:  generateAccountNumber(prefix, length),
 		AccountType: cardType(ctype),
 	}
 }
 
-func generateAccountNumber(cardType string) string {
-	var prefixes []string
-	var length int
-
-	switch cardType {
-	case "bb":
-		prefixes = []string{"123"}
-		length = 7
-	case "itau":
-		prefixes = []string{"32"}
-		length = 6
-	case "caixa":
-		prefixes = []string{"21"}
-		length = 6
-	case "santander":
-		prefixes = []string{"53"}
-		length = 9
-	default:
-		return "Invalid card type"
-	}
-
-	prefix := prefixes[generateRandomNumber(0, len(prefixes))]
+func generateAccountNumber(prefix string, length int) string {
 	number := prefix
 	for i := len(prefix); i < length-1; i++ {
-		number += strconv.Itoa(generateRandomNumber(0, 9))
+		number += strconv.Itoa(generateRandomNumber(0, 10))
 	}
 
+	number += strconv.Itoa(luhnCheckDigit(number))
+
+	return number
+}
+
+// luhnCheckDigit returns the digit that, appended to number, makes it pass
+// the Luhn check. The doubling starts at number's last digit, which sits
+// right before the check digit.
+func luhnCheckDigit(number string) int {
 	sum := 0
-	double := false
+	double := true
 	for i := len(number) - 1; i >= 0; i-- {
-		digit, _ := strconv.Atoi(string(number[i]))
+		digit := int(number[i] - '0')
 		if double {
 			digit *= 2
 			if digit > 9 {
@@ -57,8 +46,5 @@ func generateAccountNumber(cardType string) string {
 		sum += digit
 		double = !double
 	}
-	checkDigit := (10 - (sum % 10)) % 10
-	number += strconv.Itoa(checkDigit)
-
-	return number
+	return (10 - (sum % 10)) % 10
 }
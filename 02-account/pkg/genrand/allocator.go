@@ -0,0 +1,95 @@
+package genrand
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/adilsonmenechini/golabbank/platform/metrics"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the Postgres SQLSTATE for a duplicate key.
+const uniqueViolation = "23505"
+
+var ErrAllocationExhausted = errors.New("account number space exhausted")
+
+// allocations exports the same counters as Stats, for every allocator.
+var allocations = metrics.NewAllocations("account")
+
+// AllocatorStats counts what happened to allocations for one product prefix.
+type AllocatorStats struct {
+	Allocated  uint64 `json:"allocated"`
+	Collisions uint64 `json:"collisions"`
+	Exhausted  uint64 `json:"exhausted"`
+}
+
+// AccountNumberAllocator hands out random account numbers, retrying when the
+// number is already taken. Counters are kept per prefix so a product running
+// out of numbers shows up before it starts failing every signup.
+type AccountNumberAllocator struct {
+	maxAttempts int
+	mu          sync.Mutex
+	stats       map[string]*AllocatorStats
+}
+
+func NewAccountNumberAllocator(maxAttempts int) *AccountNumberAllocator {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &AccountNumberAllocator{
+		maxAttempts: maxAttempts,
+		stats:       make(map[string]*AllocatorStats),
+	}
+}
+
+// Allocate generates a length-digit number starting with prefix and passes
+// it to insert. When insert fails with a unique violation a new number is
+// drawn, up to the allocator's bound; any other error is returned as is.
+func (a *AccountNumberAllocator) Allocate(prefix string, length int, insert func(number string) error) (string, error) {
+	for attempt := 0; attempt < a.maxAttempts; attempt++ {
+		number := generateAccountNumber(prefix, length)
+		err := insert(number)
+		if err == nil {
+			a.count(prefix, func(s *AllocatorStats) { s.Allocated++ })
+			allocations.Allocated(prefix)
+			return number, nil
+		}
+		if !IsUniqueViolation(err) {
+			return "", err
+		}
+		a.count(prefix, func(s *AllocatorStats) { s.Collisions++ })
+		allocations.Collided(prefix)
+	}
+	a.count(prefix, func(s *AllocatorStats) { s.Exhausted++ })
+	allocations.Exhausted(prefix)
+	return "", fmt.Errorf("%w: prefix %s after %d attempts", ErrAllocationExhausted, prefix, a.maxAttempts)
+}
+
+// Stats returns a snapshot of the counters keyed by prefix.
+func (a *AccountNumberAllocator) Stats() map[string]AllocatorStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make(map[string]AllocatorStats, len(a.stats))
+	for prefix, s := range a.stats {
+		out[prefix] = *s
+	}
+	return out
+}
+
+func (a *AccountNumberAllocator) count(prefix string, fn func(s *AllocatorStats)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s, ok := a.stats[prefix]
+	if !ok {
+		s = &AllocatorStats{}
+		a.stats[prefix] = s
+	}
+	fn(s)
+}
+
+// IsUniqueViolation reports whether err is a Postgres duplicate-key error.
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
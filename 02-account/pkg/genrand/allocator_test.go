@@ -0,0 +1,159 @@
+package genrand
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fakeAccounts stands in for the accounts table: numbers already taken fail
+// the insert with the duplicate-key error Postgres would return.
+type fakeAccounts struct {
+	taken map[string]bool
+	tried []string
+	// collide makes every insert fail as a duplicate, as if the product's
+	// number space were full.
+	collide bool
+}
+
+func (f *fakeAccounts) CreateAccount(number string) error {
+	f.tried = append(f.tried, number)
+	if f.collide || f.taken[number] {
+		return &pq.Error{Code: uniqueViolation, Message: "duplicate key value violates unique constraint"}
+	}
+	f.taken[number] = true
+	return nil
+}
+
+// exported reads the counter name for prefix from the default registry.
+func exported(t *testing.T, name, prefix string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "prefix" && l.GetValue() == prefix {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// luhnValid checks a whole number, check digit included.
+func luhnValid(number string) bool {
+	sum := 0
+	for i := 0; i < len(number); i++ {
+		digit := int(number[len(number)-1-i] - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	tests := map[string]int{
+		"7992739871":      3,
+		"411111111111111": 1,
+		"000000":          0,
+		"0012345":         5,
+	}
+	for number, want := range tests {
+		if got := luhnCheckDigit(number); got != want {
+			t.Errorf("luhnCheckDigit(%s) = %d, want %d", number, got, want)
+		}
+	}
+}
+
+func TestAccountNumberCheckDigit(t *testing.T) {
+	for _, p := range []struct {
+		prefix string
+		length int
+	}{{"001", 10}, {"12", 7}, {"9", 16}} {
+		for i := 0; i < 200; i++ {
+			number := generateAccountNumber(p.prefix, p.length)
+			if len(number) != p.length || !strings.HasPrefix(number, p.prefix) {
+				t.Fatalf("generateAccountNumber(%s, %d) = %s", p.prefix, p.length, number)
+			}
+			if !luhnValid(number) {
+				t.Fatalf("%s fails the Luhn check", number)
+			}
+		}
+	}
+}
+
+// TestAllocateRetriesOnCollision gives the allocator a number space of ten
+// with nine numbers already taken: it must keep drawing until it lands on
+// the free one.
+func TestAllocateRetriesOnCollision(t *testing.T) {
+	repo := &fakeAccounts{taken: make(map[string]bool)}
+	for d := 0; d < 9; d++ {
+		number := "12" + strconv.Itoa(d)
+		repo.taken[number+strconv.Itoa(luhnCheckDigit(number))] = true
+	}
+	a := NewAccountNumberAllocator(1000)
+
+	number, err := a.Allocate("12", 4, repo.CreateAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "129" + strconv.Itoa(luhnCheckDigit("129")); number != want {
+		t.Fatalf("allocated %s, want the only free number %s", number, want)
+	}
+
+	s := a.Stats()["12"]
+	if s.Allocated != 1 || s.Exhausted != 0 || s.Collisions != uint64(len(repo.tried)-1) {
+		t.Fatalf("stats = %+v after %d tries", s, len(repo.tried))
+	}
+}
+
+func TestAllocateExhausted(t *testing.T) {
+	repo := &fakeAccounts{taken: make(map[string]bool), collide: true}
+	a := NewAccountNumberAllocator(5)
+	collisions := exported(t, "account_number_collisions_total", "99")
+
+	if _, err := a.Allocate("99", 7, repo.CreateAccount); !errors.Is(err, ErrAllocationExhausted) {
+		t.Fatalf("err = %v, want ErrAllocationExhausted", err)
+	}
+	if len(repo.tried) != 5 {
+		t.Fatalf("tried %d numbers, want 5", len(repo.tried))
+	}
+	if s := a.Stats()["99"]; s.Collisions != 5 || s.Exhausted != 1 || s.Allocated != 0 {
+		t.Fatalf("stats = %+v", s)
+	}
+	if got := exported(t, "account_number_collisions_total", "99") - collisions; got != 5 {
+		t.Fatalf("account_number_collisions_total grew by %v, want 5", got)
+	}
+}
+
+// TestAllocateReturnsOtherErrors must not retry an insert that failed for a
+// reason other than a taken number.
+func TestAllocateReturnsOtherErrors(t *testing.T) {
+	boom := errors.New("connection refused")
+	calls := 0
+	a := NewAccountNumberAllocator(5)
+
+	_, err := a.Allocate("12", 7, func(number string) error {
+		calls++
+		return boom
+	})
+	if !errors.Is(err, boom) || calls != 1 {
+		t.Fatalf("err = %v after %d calls, want the insert error after one", err, calls)
+	}
+}
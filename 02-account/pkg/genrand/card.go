@@ -8,7 +8,7 @@ type generateCard struct {
 	CardNumber      string
 	CardType        cardType
 	CNumber         int
-	CCV             int
+	CCV             string
 	ExpirationMonth int
 	ExpirationYear  int
 }
@@ -17,6 +17,7 @@ func GenerateCard(ctype string, cYear, cNum int) *generateCard {
 	expirationMonth, expirationYear := generateExpirationDate(cYear)
 	return &generateCard{
 		CardNumber:      generateCreditCardNumber(ctype),
+		CardType:        cardType(ctype),
 		CCV:             generateCCV(cNum),
 		ExpirationMonth: expirationMonth,
 		ExpirationYear:  expirationYear,
@@ -47,24 +48,10 @@ func generateCreditCardNumber(cardType string) string {
 	prefix := prefixes[generateRandomNumber(0, len(prefixes))]
 	number := prefix
 	for i := len(prefix); i < length-1; i++ {
-		number += strconv.Itoa(generateRandomNumber(0, 9))
+		number += strconv.Itoa(generateRandomNumber(0, 10))
 	}
 
-	sum := 0
-	double := false
-	for i := len(number) - 1; i >= 0; i-- {
-		digit, _ := strconv.Atoi(string(number[i]))
-		if double {
-			digit *= 2
-			if digit > 9 {
-				digit -= 9
-			}
-		}
-		sum += digit
-		double = !double
-	}
-	checkDigit := (10 - (sum % 10)) % 10
-	number += strconv.Itoa(checkDigit)
+	number += strconv.Itoa(luhnCheckDigit(number))
 
 	return number
 }
@@ -0,0 +1,26 @@
+package genrand
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCardNumberUsesEveryDigit draws enough card numbers that each of the
+// ten digits must show up among the random ones.
+func TestCardNumberUsesEveryDigit(t *testing.T) {
+	seen := make(map[rune]bool)
+	for i := 0; i < 200; i++ {
+		number := generateCreditCardNumber("visa")
+		if len(number) != 16 || !strings.HasPrefix(number, "4") || !luhnValid(number) {
+			t.Fatalf("generateCreditCardNumber(visa) = %s", number)
+		}
+		for _, d := range number[1 : len(number)-1] {
+			seen[d] = true
+		}
+	}
+	for d := '0'; d <= '9'; d++ {
+		if !seen[d] {
+			t.Errorf("digit %c never drawn", d)
+		}
+	}
+}
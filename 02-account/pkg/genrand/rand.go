@@ -1,25 +1,35 @@
 package genrand
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"math/big"
+	"strconv"
 	"time"
 )
 
+// generateRandomNumber returns a uniform number in [min, max) drawn from
+// crypto/rand, so card and account numbers cannot be predicted from earlier
+// ones.
 func generateRandomNumber(min, max int) int {
-	return rand.Intn(max-min) + min
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max-min)))
+	if err != nil {
+		panic(err)
+	}
+	return int(n.Int64()) + min
 }
 func generateExpirationDate(y int) (int, int) {
 	// Gera um mês e um ano aleatórios para a data de expiração do cartão
 	currentYear := time.Now().Year()
 	year := currentYear + y
-	month := generateRandomNumber(1, 12)
+	month := generateRandomNumber(1, 13)
 	return month, year
 }
 
-func generateCCV(c int) int {
-	ccv := 0
+// generateCCV returns c random digits, keeping leading zeros.
+func generateCCV(c int) string {
+	ccv := ""
 	for i := 0; i < c; i++ {
-		ccv += generateRandomNumber(0, 9)
+		ccv += strconv.Itoa(generateRandomNumber(0, 10))
 	}
 	return ccv
 }
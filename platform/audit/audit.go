@@ -0,0 +1,62 @@
+// Package audit keeps a record of security-relevant decisions, such as denied
+// access or locked-out sign-ins, apart from the ordinary application log.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+const (
+	OutcomeAllowed = "allowed"
+	OutcomeDenied  = "denied"
+	OutcomeFailed  = "failed"
+)
+
+// Event is a security-relevant fact that must be kept for later review.
+// Operator and Role name the staff member who acted, when it was not the
+// customer themselves.
+type Event struct {
+	Action     string    `json:"action"`
+	CustomerID string    `json:"customer_id"`
+	Operator   string    `json:"operator,omitempty"`
+	Role       string    `json:"role,omitempty"`
+	Resource   string    `json:"resource"`
+	Outcome    string    `json:"outcome"`
+	Detail     string    `json:"detail,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+type (
+	Auditor interface {
+		Record(ctx context.Context, e Event)
+	}
+
+	logAuditor struct {
+		logger *utils.Logger
+	}
+)
+
+// Record implements Auditor.
+func (la *logAuditor) Record(ctx context.Context, e Event) {
+	if e.At.IsZero() {
+		e.At = time.Now().UTC()
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		la.logger.WithContext(ctx).Errorf("error encoding audit event: %v", err)
+		return
+	}
+	la.logger.WithContext(ctx).Warnf("%s", b)
+}
+
+// NewLogAuditor writes audit events as JSON lines through the application
+// logger.
+func NewLogAuditor() Auditor {
+	return &logAuditor{
+		logger: utils.NewLogger("audit"),
+	}
+}
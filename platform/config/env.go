@@ -0,0 +1,75 @@
+// Package config reads the settings every bank service shares from the
+// environment.
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type EnvConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBname   string
+	Token    string
+}
+
+// Load reads a service's .env file into the environment.
+func Load(path string) {
+	err := godotenv.Load(path)
+
+	if err != nil {
+		log.Fatalf("Error loading .env files")
+	}
+}
+
+// GetDuration reads a duration such as "24h" from the environment, falling
+// back to def when the variable is unset or malformed.
+func GetDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid duration %q for %s, using %s", v, key, def)
+		return def
+	}
+	return d
+}
+
+// GetInt reads an integer from the environment, falling back to def when the
+// variable is unset or malformed.
+func GetInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("invalid integer %q for %s, using %d", v, key, def)
+		return def
+	}
+	return n
+}
+
+// GetBool reads a boolean such as "true" or "0" from the environment,
+// falling back to def when the variable is unset or malformed.
+func GetBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("invalid boolean %q for %s, using %t", v, key, def)
+		return def
+	}
+	return b
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/adilsonmenechini/golabbank/platform/config"
+	"github.com/adilsonmenechini/golabbank/platform/tracing"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/lib/pq"
+)
+
+var ErrMissingConfig = errors.New("missing database configuration")
+
+var logger *utils.Logger
+
+func init() {
+	logger = utils.NewLogger("database")
+}
+
+func dsnPsql() (string, error) {
+	dbEnv := &config.EnvConfig{
+		Host:     os.Getenv("DB_HOST"),
+		Port:     os.Getenv("DB_PORT"),
+		User:     os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASSWORD"),
+		DBname:   os.Getenv("DB_NAME"),
+	}
+	host, port, user, password, dbName := dbEnv.Host, dbEnv.Port, dbEnv.User, dbEnv.Password, dbEnv.DBname
+	if host == "" || port == "" || user == "" || password == "" || dbName == "" {
+		return "", fmt.Errorf("%w: DB_HOST, DB_PORT, DB_USER, DB_PASSWORD and DB_NAME are required", ErrMissingConfig)
+	}
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", host, port, user, password, dbName), nil
+}
+
+// ConnectPSQL opens the pool described by the DB_* variables and pings it,
+// so a service fails at startup rather than on its first request when the
+// database cannot be reached. Every statement run on the pool is traced.
+func ConnectPSQL(ctx context.Context) (*sql.DB, error) {
+	dsn, err := dsnPsql()
+	if err != nil {
+		return nil, err
+	}
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	db := sql.OpenDB(tracing.Connector(connector, "postgresql", os.Getenv("DB_NAME")))
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("pinging database: %w", err)
+	}
+	logger.Infof("Connected to database successfully")
+	return db, nil
+}
@@ -0,0 +1,31 @@
+// Package identity holds the customer record both services agree on.
+package identity
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrCustomerNotFound = errors.New("customer not found")
+
+type Customer struct {
+	ID        string
+	Name      string
+	Email     string
+	Password  string
+	Role      Role
+	CreatedAt time.Time
+}
+
+// NewCustomer returns a customer record with the customer role. passwordHash
+// must already be hashed, see the password package.
+func NewCustomer(id, name, email, passwordHash string) Customer {
+	return Customer{
+		ID:        id,
+		Name:      name,
+		Email:     email,
+		Password:  passwordHash,
+		Role:      RoleCustomer,
+		CreatedAt: time.Now().UTC(),
+	}
+}
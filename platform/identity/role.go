@@ -0,0 +1,39 @@
+package identity
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownRole = errors.New("unknown role")
+
+// Role decides what a signed-in user may do. Everyone who signs up is a
+// customer; staff roles are granted by an admin.
+type Role string
+
+const (
+	// RoleCustomer operates on their own accounts only.
+	RoleCustomer Role = "customer"
+	// RoleTeller serves customers at the counter: looks accounts up and posts
+	// cash deposits and withdrawals on their behalf.
+	RoleTeller Role = "teller"
+	// RoleAdmin runs the back office: customers, roles, account status,
+	// limits and reversals.
+	RoleAdmin Role = "admin"
+	// RoleAuditor reads everything the back office can see and changes
+	// nothing.
+	RoleAuditor Role = "auditor"
+)
+
+// ParseRole returns the role named s. An empty s is a customer, which is
+// what tokens issued before roles existed hold.
+func ParseRole(s string) (Role, error) {
+	switch r := Role(s); r {
+	case "":
+		return RoleCustomer, nil
+	case RoleCustomer, RoleTeller, RoleAdmin, RoleAuditor:
+		return r, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownRole, s)
+	}
+}
@@ -0,0 +1,185 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultIssuer   = "golabbank"
+	defaultAudience = "golabbank"
+	jwksTimeout     = 5 * time.Second
+	jwksMinRefresh  = time.Minute
+	jwksMaxAge      = 5 * time.Minute
+)
+
+// FromEnv builds a key ring from the environment:
+//
+//	JWT_ISSUER, JWT_AUDIENCE   claims to stamp and require (default golabbank)
+//	JWT_ALGORITHMS             comma-separated allow list (default: the keys' algorithms)
+//	JWT_PRIVATE_KEY_FILE       PEM RSA or Ed25519 signing key
+//	JWT_KEY_ID                 kid of the signing key (default: its thumbprint, or "hs256")
+//	JWT_SECRET                 HS256 signing secret, used when no private key file is set
+//	JWT_PREVIOUS_KEY_FILES     comma-separated PEM keys still accepted after a rotation
+//	JWT_JWKS_FILE, JWT_JWKS_URL  public keys of another service to accept, reloaded every jwksMaxAge
+//
+// It must run after the .env file has been loaded.
+func FromEnv() (*KeyRing, error) {
+	opts := Options{
+		Issuer:   envOr("JWT_ISSUER", defaultIssuer),
+		Audience: envOr("JWT_AUDIENCE", defaultAudience),
+	}
+	if algs := os.Getenv("JWT_ALGORITHMS"); algs != "" {
+		opts.Algorithms = splitList(algs)
+	}
+
+	signing, err := signingKeyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	var verify []*Key
+	for _, path := range splitList(os.Getenv("JWT_PREVIOUS_KEY_FILES")) {
+		k, err := readPEMKey(path, "")
+		if err != nil {
+			return nil, err
+		}
+		verify = append(verify, k)
+	}
+
+	var fetch func() ([]*Key, error)
+	if path := os.Getenv("JWT_JWKS_FILE"); path != "" {
+		fetch = func() ([]*Key, error) { return readJWKSFile(path) }
+	} else if url := os.Getenv("JWT_JWKS_URL"); url != "" {
+		fetch = func() ([]*Key, error) { return FetchJWKS(url) }
+	}
+	var fetched []*Key
+	if fetch != nil {
+		fetched, err = fetch()
+		if err != nil {
+			return nil, fmt.Errorf("loading JWKS: %w", err)
+		}
+	}
+
+	if len(opts.Algorithms) == 0 && len(fetched) > 0 {
+		// New allows the algorithms of the keys it is given; those fetched
+		// must count too.
+		all := append(append([]*Key{}, verify...), fetched...)
+		if signing != nil {
+			all = append(all, signing)
+		}
+		for _, k := range all {
+			if !slices.Contains(opts.Algorithms, k.Alg) {
+				opts.Algorithms = append(opts.Algorithms, k.Alg)
+			}
+		}
+	}
+
+	kr, err := New(signing, verify, opts)
+	if err != nil {
+		return nil, err
+	}
+	if fetch != nil {
+		kr.WithRefresh(fetch, fetched, jwksMinRefresh, jwksMaxAge)
+	}
+	return kr, nil
+}
+
+func signingKeyFromEnv() (*Key, error) {
+	kid := os.Getenv("JWT_KEY_ID")
+	if path := os.Getenv("JWT_PRIVATE_KEY_FILE"); path != "" {
+		return readPEMKey(path, kid)
+	}
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		if kid == "" {
+			kid = "hs256"
+		}
+		return NewHMACKey(kid, []byte(secret))
+	}
+	return nil, nil
+}
+
+// readPEMKey loads a PKCS#8 or PKCS#1 private key, or a PKIX public key.
+func readPEMKey(path, kid string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block in %s", ErrUnsupportedKey, path)
+	}
+	switch block.Type {
+	case "PRIVATE KEY":
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := priv.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, priv)
+		}
+		return NewPrivateKey(kid, signer)
+	case "RSA PRIVATE KEY":
+		priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return NewPrivateKey(kid, priv)
+	case "PUBLIC KEY":
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return NewPublicKey(kid, pub)
+	default:
+		return nil, fmt.Errorf("%w: PEM type %s", ErrUnsupportedKey, block.Type)
+	}
+}
+
+func readJWKSFile(path string) ([]*Key, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadJWKS(f)
+}
+
+// FetchJWKS downloads a JWK set.
+func FetchJWKS(url string) ([]*Key, error) {
+	client := &http.Client{Timeout: jwksTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %d", url, resp.StatusCode)
+	}
+	return ReadJWKS(resp.Body)
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+)
+
+// JWK is the public part of a key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the ring, sorted by kid. HMAC keys are
+// secret and never included.
+func (kr *KeyRing) JWKS() JWKSet {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	set := JWKSet{Keys: []JWK{}}
+	for _, k := range kr.keys {
+		if k.Alg == HS256 {
+			continue
+		}
+		set.Keys = append(set.Keys, toJWK(k))
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// ReadJWKS parses a JWK set into verification keys.
+func ReadJWKS(r io.Reader) ([]*Key, error) {
+	var set JWKSet
+	if err := json.NewDecoder(r).Decode(&set); err != nil {
+		return nil, err
+	}
+	keys := make([]*Key, 0, len(set.Keys))
+	for _, j := range set.Keys {
+		k, err := fromJWK(j)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func toJWK(k *Key) JWK {
+	j := JWK{Kid: k.ID, Alg: k.Alg, Use: "sig"}
+	switch pub := k.verify.(type) {
+	case *rsa.PublicKey:
+		j.Kty = "RSA"
+		j.N = b64(pub.N.Bytes())
+		j.E = b64(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		j.Kty = "OKP"
+		j.Crv = "Ed25519"
+		j.X = b64(pub)
+	}
+	return j
+}
+
+func fromJWK(j JWK) (*Key, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := unb64(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := unb64(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return NewPublicKey(j.Kid, &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())})
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKey, j.Crv)
+		}
+		x, err := unb64(j.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: bad Ed25519 key size", ErrUnsupportedKey)
+		}
+		return NewPublicKey(j.Kid, ed25519.PublicKey(x))
+	default:
+		return nil, fmt.Errorf("%w: kty %s", ErrUnsupportedKey, j.Kty)
+	}
+}
+
+// thumbprint is the RFC 7638 SHA-256 thumbprint of a public JWK.
+func thumbprint(j JWK) string {
+	var canonical string
+	switch j.Kty {
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, j.E, j.N)
+	case "OKP":
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, j.Crv, j.X)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return b64(sum[:])
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func unb64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,285 @@
+// Package keyring holds the keys used to sign and verify access tokens. It
+// supports HMAC (HS256), RSA (RS256) and Ed25519 (EdDSA) keys, identifies
+// every key by a kid so keys can be rotated, and only accepts tokens whose
+// algorithm is on an explicit allow list.
+package keyring
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+var (
+	ErrNoSigningKey        = errors.New("no token signing key configured")
+	ErrUnknownKey          = errors.New("unknown token key")
+	ErrAlgorithmNotAllowed = errors.New("token algorithm not allowed")
+	ErrUnsupportedKey      = errors.New("unsupported key type")
+)
+
+// Key is one signing or verification key.
+type Key struct {
+	ID     string
+	Alg    string
+	sign   any
+	verify any
+}
+
+// NewHMACKey returns an HS256 key. HMAC keys are never published in a JWKS.
+func NewHMACKey(kid string, secret []byte) (*Key, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("%w: empty HMAC secret", ErrUnsupportedKey)
+	}
+	return &Key{ID: kid, Alg: HS256, sign: secret, verify: secret}, nil
+}
+
+// NewPrivateKey returns a signing key for an RSA or Ed25519 private key. An
+// empty kid is replaced by the key's RFC 7638 thumbprint.
+func NewPrivateKey(kid string, priv crypto.Signer) (*Key, error) {
+	k, err := NewPublicKey(kid, priv.Public())
+	if err != nil {
+		return nil, err
+	}
+	k.sign = priv
+	return k, nil
+}
+
+// NewPublicKey returns a verification-only key.
+func NewPublicKey(kid string, pub crypto.PublicKey) (*Key, error) {
+	k := &Key{ID: kid, verify: pub}
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("%w: RSA keys must be at least 2048 bits", ErrUnsupportedKey)
+		}
+		k.Alg = RS256
+	case ed25519.PublicKey:
+		k.Alg = EdDSA
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, pub)
+	}
+	if k.ID == "" {
+		k.ID = thumbprint(toJWK(k))
+	}
+	return k, nil
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Alg)
+}
+
+// Options are the claims every token must carry.
+type Options struct {
+	Issuer     string
+	Audience   string
+	Algorithms []string
+}
+
+// KeyRing signs tokens with one active key and verifies them with any key it
+// knows, looked up by the kid header.
+type KeyRing struct {
+	mu      sync.RWMutex
+	signing *Key
+	// local holds the keys given to New; keys adds those last fetched.
+	local   map[string]*Key
+	keys    map[string]*Key
+	allowed map[string]bool
+	opts    Options
+
+	refresh     func() ([]*Key, error)
+	minInterval time.Duration
+	maxAge      time.Duration
+	lastRefresh time.Time
+	fetchedAt   time.Time
+}
+
+// New builds a key ring. signing may be nil for a service that only verifies
+// tokens. When opts.Algorithms is empty, the algorithms of the given keys are
+// allowed.
+func New(signing *Key, verify []*Key, opts Options) (*KeyRing, error) {
+	kr := &KeyRing{
+		signing: signing,
+		keys:    make(map[string]*Key),
+		allowed: make(map[string]bool),
+		opts:    opts,
+	}
+	all := verify
+	if signing != nil {
+		all = append([]*Key{signing}, verify...)
+	}
+	for _, alg := range opts.Algorithms {
+		kr.allowed[alg] = true
+	}
+	if len(kr.allowed) == 0 {
+		for _, k := range all {
+			kr.allowed[k.Alg] = true
+		}
+	}
+	for _, k := range all {
+		if !kr.allowed[k.Alg] {
+			return nil, fmt.Errorf("%w: key %s uses %s", ErrAlgorithmNotAllowed, k.ID, k.Alg)
+		}
+		kr.keys[k.ID] = k
+	}
+	kr.local = kr.keys
+	return kr, nil
+}
+
+// WithRefresh makes the ring verify with the keys fetch returns besides its
+// own, starting with fetched, and reload them through fetch at most once per
+// minInterval: when a token names an unknown kid, the first one right away,
+// and when the fetched keys are older than maxAge. Each reload replaces the
+// fetched keys, so a kid the issuer stopped publishing is no longer trusted
+// after maxAge.
+func (kr *KeyRing) WithRefresh(fetch func() ([]*Key, error), fetched []*Key, minInterval, maxAge time.Duration) *KeyRing {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.refresh = fetch
+	kr.minInterval = minInterval
+	kr.maxAge = maxAge
+	kr.replace(fetched)
+	return kr
+}
+
+// Stamp sets the issuer and audience every token from this ring carries.
+func (kr *KeyRing) Stamp(rc *jwt.RegisteredClaims) {
+	rc.Issuer = kr.opts.Issuer
+	if kr.opts.Audience != "" {
+		rc.Audience = jwt.ClaimStrings{kr.opts.Audience}
+	}
+}
+
+// Sign signs claims with the active key and records its kid in the header.
+func (kr *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	kr.mu.RLock()
+	k := kr.signing
+	kr.mu.RUnlock()
+	if k == nil || k.sign == nil {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(k.method(), claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.sign)
+}
+
+// Parse verifies token into claims. The algorithm must be allowed and match
+// the key named by kid, and issuer and audience must match when configured.
+func (kr *KeyRing) Parse(token string, claims jwt.Claims) error {
+	opts := []jwt.ParserOption{jwt.WithValidMethods(kr.algorithms()), jwt.WithExpirationRequired()}
+	if kr.opts.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(kr.opts.Issuer))
+	}
+	if kr.opts.Audience != "" {
+		opts = append(opts, jwt.WithAudience(kr.opts.Audience))
+	}
+	_, err := jwt.ParseWithClaims(token, claims, kr.keyFunc, opts...)
+	return err
+}
+
+func (kr *KeyRing) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	k, err := kr.lookup(kid)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != k.Alg {
+		return nil, fmt.Errorf("%w: key %s is %s, token is %s", ErrAlgorithmNotAllowed, k.ID, k.Alg, token.Method.Alg())
+	}
+	return k.verify, nil
+}
+
+func (kr *KeyRing) lookup(kid string) (*Key, error) {
+	k, ok, stale := kr.find(kid)
+	if ok && !stale {
+		return k, nil
+	}
+	if err := kr.reload(); err != nil {
+		if ok {
+			// Keep trusting the last set fetched while the issuer is
+			// unreachable; the next reload is at most minInterval away.
+			return k, nil
+		}
+		return nil, err
+	}
+	if k, ok, _ := kr.find(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+// find looks kid up in the current set, reporting whether the fetched keys
+// are due for a reload.
+func (kr *KeyRing) find(kid string) (*Key, bool, bool) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	k, ok := kr.keys[kid]
+	if !ok && kid == "" && kr.signing != nil {
+		// Tokens signed before kids were introduced.
+		k, ok = kr.signing, true
+	}
+	stale := kr.refresh != nil && kr.maxAge > 0 && time.Since(kr.fetchedAt) >= kr.maxAge
+	return k, ok, stale
+}
+
+// reload fetches the verification keys again, at most once per minInterval.
+// The fetch may be a network call, so it runs without the lock; lookups of
+// known keys and signing carry on meanwhile, and the new set is swapped in
+// when it arrives.
+func (kr *KeyRing) reload() error {
+	kr.mu.Lock()
+	if kr.refresh == nil || time.Since(kr.lastRefresh) < kr.minInterval {
+		kr.mu.Unlock()
+		return nil
+	}
+	// Claim this interval before fetching so concurrent misses do not all
+	// hit the key server.
+	kr.lastRefresh = time.Now()
+	fetch := kr.refresh
+	kr.mu.Unlock()
+
+	fetched, err := fetch()
+	if err != nil {
+		return err
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.replace(fetched)
+	return nil
+}
+
+// replace sets the verification keys to the ring's own plus fetched, dropping
+// those fetched before. The caller holds the lock.
+func (kr *KeyRing) replace(fetched []*Key) {
+	keys := make(map[string]*Key, len(kr.local)+len(fetched))
+	for _, k := range fetched {
+		if kr.allowed[k.Alg] {
+			keys[k.ID] = k
+		}
+	}
+	for id, k := range kr.local {
+		keys[id] = k
+	}
+	kr.keys = keys
+	kr.fetchedAt = time.Now()
+}
+
+func (kr *KeyRing) algorithms() []string {
+	out := make([]string, 0, len(kr.allowed))
+	for alg := range kr.allowed {
+		out = append(out, alg)
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func claims() *jwt.RegisteredClaims {
+	return &jwt.RegisteredClaims{Subject: "alice", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+}
+
+// TestReloadDoesNotBlockKnownKeys parks a JWKS fetch and checks that signing
+// and verifying with a known key still go through until it returns.
+func TestReloadDoesNotBlockKnownKeys(t *testing.T) {
+	current, err := NewHMACKey("current", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := NewPrivateKey("rotated", priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	published, err := NewPublicKey("rotated", priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kr, err := New(current, nil, Options{Algorithms: []string{HS256, EdDSA}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	kr.WithRefresh(func() ([]*Key, error) {
+		close(fetching)
+		<-release
+		return []*Key{published}, nil
+	}, nil, time.Hour, 0)
+
+	other, err := New(rotated, nil, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown, err := other.Sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first unknown kid reloads at once, not after minInterval.
+	parsed := make(chan error, 1)
+	go func() { parsed <- kr.Parse(unknown, &jwt.RegisteredClaims{}) }()
+	select {
+	case <-fetching:
+	case <-time.After(time.Second):
+		t.Fatal("an unknown kid did not trigger a reload")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		token, err := kr.Sign(claims())
+		if err == nil {
+			err = kr.Parse(token, &jwt.RegisteredClaims{})
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("signing with a known key waited for the JWKS fetch")
+	}
+
+	close(release)
+	if err := <-parsed; err != nil {
+		t.Fatalf("token signed with the fetched key: %v", err)
+	}
+}
+
+// TestRotatedOutKeyIsDropped stops publishing a kid and checks that tokens
+// signed with it are refused once the fetched keys are reloaded, while the
+// ring's own key keeps working.
+func TestRotatedOutKeyIsDropped(t *testing.T) {
+	own, err := NewHMACKey("own", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var issuers []*KeyRing
+	var published []*Key
+	for _, kid := range []string{"old", "new"} {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signing, err := NewPrivateKey(kid, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		issuer, err := New(signing, nil, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := NewPublicKey(kid, priv.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		issuers = append(issuers, issuer)
+		published = append(published, pub)
+	}
+
+	kr, err := New(own, nil, Options{Algorithms: []string{HS256, EdDSA}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := published
+	kr.WithRefresh(func() ([]*Key, error) { return jwks, nil }, jwks, 0, 20*time.Millisecond)
+
+	oldToken, err := issuers[0].Sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kr.Parse(oldToken, &jwt.RegisteredClaims{}); err != nil {
+		t.Fatalf("token signed with a published key: %v", err)
+	}
+
+	// The issuer rotates "old" out.
+	jwks = published[1:]
+	time.Sleep(30 * time.Millisecond)
+	if err := kr.Parse(oldToken, &jwt.RegisteredClaims{}); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("token signed with a key no longer published: err = %v, want ErrUnknownKey", err)
+	}
+
+	newToken, err := issuers[1].Sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ownToken, err := kr.Sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, token := range map[string]string{"published key": newToken, "own key": ownToken} {
+		if err := kr.Parse(token, &jwt.RegisteredClaims{}); err != nil {
+			t.Fatalf("token signed with the %s: %v", name, err)
+		}
+	}
+}
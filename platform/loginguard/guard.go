@@ -0,0 +1,298 @@
+// Package loginguard slows down password guessing. Failed sign-ins are
+// counted per email and per client IP; each failure past a free allowance
+// doubles the wait before the next attempt, and enough failures lock the key
+// out for a while.
+//
+// Keys are counted whether or not an account exists for the email, so the
+// guard's answers say nothing about which emails are registered. The price is
+// that anyone can lock a known email out by guessing on purpose; lockouts are
+// therefore temporary and written to the audit log.
+package loginguard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/config"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+var (
+	ErrThrottled    = errors.New("too many failed sign-in attempts")
+	ErrUnknownStore = errors.New("unknown login guard store")
+)
+
+// ThrottledError refuses a sign-in before the password is looked at.
+type ThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", ErrThrottled, e.RetryAfter.Round(time.Second))
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return ErrThrottled
+}
+
+// Limits applies to one kind of key.
+type Limits struct {
+	// FreeFailures are forgiven before any backoff applies.
+	FreeFailures int
+	// MaxFailures locks the key out for Lockout; zero never locks.
+	MaxFailures int
+	Lockout     time.Duration
+}
+
+// Policy holds the limits per kind of key. Backoff starts at BackoffBase,
+// doubles with every further failure and stops growing at BackoffMax.
+// Failures are forgotten once a key has been quiet for Window.
+type Policy struct {
+	Email       Limits
+	IP          Limits
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	Window      time.Duration
+}
+
+// DefaultPolicy allows a typo per email before slowing down and locks an
+// email for 15 minutes after 5 failures. An IP may be shared by many
+// customers behind NAT, so it gets far more room.
+func DefaultPolicy() Policy {
+	return Policy{
+		Email:       Limits{FreeFailures: 1, MaxFailures: 5, Lockout: 15 * time.Minute},
+		IP:          Limits{FreeFailures: 10, MaxFailures: 100, Lockout: time.Hour},
+		BackoffBase: time.Second,
+		BackoffMax:  time.Minute,
+		Window:      time.Hour,
+	}
+}
+
+// PolicyFromEnv overrides DefaultPolicy with LOGIN_EMAIL_FREE_FAILURES,
+// LOGIN_EMAIL_MAX_FAILURES, LOGIN_EMAIL_LOCKOUT, the same three for
+// LOGIN_IP_, LOGIN_BACKOFF_BASE, LOGIN_BACKOFF_MAX and LOGIN_WINDOW.
+func PolicyFromEnv() Policy {
+	p := DefaultPolicy()
+	return Policy{
+		Email:       limitsFromEnv("LOGIN_EMAIL_", p.Email),
+		IP:          limitsFromEnv("LOGIN_IP_", p.IP),
+		BackoffBase: config.GetDuration("LOGIN_BACKOFF_BASE", p.BackoffBase),
+		BackoffMax:  config.GetDuration("LOGIN_BACKOFF_MAX", p.BackoffMax),
+		Window:      config.GetDuration("LOGIN_WINDOW", p.Window),
+	}
+}
+
+func limitsFromEnv(prefix string, def Limits) Limits {
+	return Limits{
+		FreeFailures: config.GetInt(prefix+"FREE_FAILURES", def.FreeFailures),
+		MaxFailures:  config.GetInt(prefix+"MAX_FAILURES", def.MaxFailures),
+		Lockout:      config.GetDuration(prefix+"LOCKOUT", def.Lockout),
+	}
+}
+
+// backoff is the wait imposed after the given number of failures.
+func (p Policy) backoff(l Limits, failures int) time.Duration {
+	n := failures - l.FreeFailures
+	if n <= 0 || p.BackoffBase <= 0 {
+		return 0
+	}
+	d := time.Duration(float64(p.BackoffBase) * math.Pow(2, float64(n-1)))
+	if d <= 0 || (p.BackoffMax > 0 && d > p.BackoffMax) {
+		d = p.BackoffMax
+	}
+	return d
+}
+
+type Guard struct {
+	logger  *utils.Logger
+	store   Store
+	policy  Policy
+	auditor audit.Auditor
+}
+
+func NewGuard(store Store, policy Policy, auditor audit.Auditor) *Guard {
+	return &Guard{
+		logger:  utils.NewLogger("loginGuard"),
+		store:   store,
+		policy:  policy,
+		auditor: auditor,
+	}
+}
+
+// FromEnv builds a guard with PolicyFromEnv, keeping its state in the
+// store named by LOGIN_GUARD_STORE: "postgres" (the default), shared by every
+// instance through db, or "memory", local to this process.
+func FromEnv(db *sql.DB, auditor audit.Auditor) (*Guard, error) {
+	var store Store
+	switch name := os.Getenv("LOGIN_GUARD_STORE"); name {
+	case "", "postgres":
+		store = NewPostgresStore(db)
+	case "memory":
+		store = NewMemoryStore()
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStore, name)
+	}
+	return NewGuard(store, PolicyFromEnv(), auditor), nil
+}
+
+type key struct {
+	id     string
+	limits Limits
+}
+
+func (g *Guard) keys(email, ip string) []key {
+	ks := []key{{id: "email:" + strings.ToLower(strings.TrimSpace(email)), limits: g.policy.Email}}
+	if ip != "" {
+		ks = append(ks, key{id: "ip:" + ip, limits: g.policy.IP})
+	}
+	return ks
+}
+
+// Check returns a *ThrottledError when a sign-in for email from ip must wait,
+// naming the longest wait of the two keys. Otherwise the attempt is counted
+// as a failure right away, before the password is looked at, so that
+// concurrent guesses cannot all pass the check before any of them fails:
+// a guess that lost the race to another is refused too. The caller then
+// reports the outcome with Fail or Succeed.
+func (g *Guard) Check(ctx context.Context, email, ip string) error {
+	now := time.Now().UTC()
+	since := now.Add(-g.policy.Window)
+	keys := g.keys(email, ip)
+	seen := make([]Record, len(keys))
+	var refused *ThrottledError
+	for i, k := range keys {
+		rec, err := g.store.Get(ctx, k.id)
+		if err != nil {
+			return err
+		}
+		seen[i] = rec
+		te := &ThrottledError{}
+		switch {
+		case rec.LockedUntil.After(now):
+			te.RetryAfter, te.Locked = rec.LockedUntil.Sub(now), true
+		case rec.Failures == 0 || rec.LastFailure.Before(since):
+			continue
+		default:
+			te.RetryAfter = rec.LastFailure.Add(g.policy.backoff(k.limits, rec.Failures)).Sub(now)
+		}
+		if te.RetryAfter > 0 && (refused == nil || te.RetryAfter > refused.RetryAfter) {
+			refused = te
+		}
+	}
+	if refused != nil {
+		return refused
+	}
+
+	for i, k := range keys {
+		rec, err := g.store.Fail(ctx, k.id, now, since)
+		if err != nil {
+			g.release(ctx, keys[:i])
+			return err
+		}
+		want := seen[i].Failures + 1
+		if seen[i].LastFailure.Before(since) {
+			want = 1
+		}
+		wait := g.policy.backoff(k.limits, rec.Failures-1)
+		overMax := k.limits.MaxFailures > 0 && rec.Failures > k.limits.MaxFailures
+		if (rec.Failures > want && wait > 0) || overMax {
+			if refused == nil || wait > refused.RetryAfter {
+				refused = &ThrottledError{RetryAfter: wait}
+			}
+		}
+	}
+	if refused != nil {
+		g.release(ctx, keys)
+		return refused
+	}
+	return nil
+}
+
+// release takes back the attempts Check counted for keys.
+func (g *Guard) release(ctx context.Context, keys []key) {
+	for _, k := range keys {
+		if err := g.store.Forgive(ctx, k.id); err != nil {
+			g.logger.WithContext(ctx).Errorf("error releasing sign-in attempt for %s: %v", k.id, err)
+		}
+	}
+}
+
+// Fail reports that the sign-in Check let through for email from ip failed.
+// Check already counted it; Fail locks out any key that reached its
+// MaxFailures.
+func (g *Guard) Fail(ctx context.Context, email, ip string) error {
+	now := time.Now().UTC()
+	for _, k := range g.keys(email, ip) {
+		if k.limits.MaxFailures <= 0 {
+			continue
+		}
+		rec, err := g.store.Get(ctx, k.id)
+		if err != nil {
+			return err
+		}
+		if rec.Failures < k.limits.MaxFailures || rec.LockedUntil.After(now) {
+			continue
+		}
+		until := now.Add(k.limits.Lockout)
+		if err := g.store.Lock(ctx, k.id, until); err != nil {
+			return err
+		}
+		g.logger.WithContext(ctx).Warnf("locked out %s after %d failed sign-ins", k.id, rec.Failures)
+		g.auditor.Record(ctx, audit.Event{
+			Action:   "login.lockout",
+			Resource: k.id,
+			Outcome:  audit.OutcomeDenied,
+			Detail:   fmt.Sprintf("%d failed sign-ins, locked until %s", rec.Failures, until.Format(time.RFC3339)),
+			At:       now,
+		})
+	}
+	return nil
+}
+
+// Succeed reports that the sign-in Check let through for email from ip
+// succeeded. The failures of email are forgotten; the IP only gets back the
+// attempt Check counted, or a guesser could clear its failures by signing in
+// to an account of their own now and then.
+func (g *Guard) Succeed(ctx context.Context, email, ip string) error {
+	keys := g.keys(email, ip)
+	if err := g.store.Reset(ctx, keys[0].id); err != nil {
+		return err
+	}
+	for _, k := range keys[1:] {
+		if err := g.store.Forgive(ctx, k.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sweep deletes keys that have been quiet for the policy window and are not
+// locked every interval until ctx is done.
+func (g *Guard) Sweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC()
+			n, err := g.store.Purge(ctx, now.Add(-g.policy.Window), now)
+			if err != nil {
+				g.logger.Errorf("error deleting stale login attempts: %v", err)
+				continue
+			}
+			if n > 0 {
+				g.logger.Infof("deleted %d stale login attempts", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package loginguard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+)
+
+func testPolicy() Policy {
+	return Policy{
+		Email:       Limits{FreeFailures: 1, MaxFailures: 3, Lockout: time.Hour},
+		IP:          Limits{FreeFailures: 100, MaxFailures: 1000, Lockout: time.Hour},
+		BackoffBase: time.Hour,
+		BackoffMax:  time.Hour,
+		Window:      time.Hour,
+	}
+}
+
+// TestCheckReservesTheAttempt sends concurrent guesses that all pass through
+// Check before any of them is reported as failed; only the free allowance
+// plus one may reach the password.
+func TestCheckReservesTheAttempt(t *testing.T) {
+	g := NewGuard(NewMemoryStore(), testPolicy(), audit.NewLogAuditor())
+	ctx := context.Background()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+		start   = make(chan struct{})
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			err := g.Check(ctx, "alice@example.com", "1.1.1.1")
+			if err == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+				return
+			}
+			if !errors.Is(err, ErrThrottled) {
+				t.Error(err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if allowed > 2 {
+		t.Fatalf("%d concurrent guesses reached the password, want at most 2", allowed)
+	}
+	rec, _ := g.store.Get(ctx, "email:alice@example.com")
+	if rec.Failures != allowed {
+		t.Fatalf("%d failures counted for %d attempts let through", rec.Failures, allowed)
+	}
+}
+
+func TestFailLocksOutAndSucceedForgets(t *testing.T) {
+	g := NewGuard(NewMemoryStore(), testPolicy(), audit.NewLogAuditor())
+	g.policy.BackoffBase, g.policy.BackoffMax = 0, 0
+	ctx := context.Background()
+
+	if err := g.Check(ctx, "bob@example.com", "2.2.2.2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Succeed(ctx, "bob@example.com", "2.2.2.2"); err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ := g.store.Get(ctx, "ip:2.2.2.2"); rec.Failures != 0 {
+		t.Fatalf("IP has %d failures after a successful sign-in, want 0", rec.Failures)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := g.Check(ctx, "bob@example.com", "2.2.2.2"); err != nil {
+			t.Fatalf("attempt %d: %v", i+1, err)
+		}
+		if err := g.Fail(ctx, "bob@example.com", "2.2.2.2"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var te *ThrottledError
+	if err := g.Check(ctx, "bob@example.com", "2.2.2.2"); !errors.As(err, &te) || !te.Locked {
+		t.Fatalf("after MaxFailures: err = %v, want a lockout", err)
+	}
+}
@@ -0,0 +1,91 @@
+package loginguard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type postgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore keeps records in the login_attempts table, so that every
+// instance of a service counts against the same limits.
+func NewPostgresStore(db *sql.DB) Store {
+	return &postgresStore{db: db}
+}
+
+// Get implements Store.
+func (ps *postgresStore) Get(ctx context.Context, key string) (Record, error) {
+	rec, err := scanRecord(ps.db.QueryRowContext(ctx, getAttempt, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Record{}, nil
+	}
+	return rec, err
+}
+
+// Fail implements Store. The count is updated by a single upsert, so
+// concurrent failures are never lost.
+func (ps *postgresStore) Fail(ctx context.Context, key string, at, since time.Time) (Record, error) {
+	return scanRecord(ps.db.QueryRowContext(ctx, failAttempt, key, at.UTC(), since.UTC()))
+}
+
+// Forgive implements Store.
+func (ps *postgresStore) Forgive(ctx context.Context, key string) error {
+	_, err := ps.db.ExecContext(ctx, forgiveAttempt, key)
+	return err
+}
+
+// Lock implements Store.
+func (ps *postgresStore) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := ps.db.ExecContext(ctx, lockAttempt, key, until.UTC())
+	return err
+}
+
+// Reset implements Store.
+func (ps *postgresStore) Reset(ctx context.Context, key string) error {
+	_, err := ps.db.ExecContext(ctx, deleteAttempt, key)
+	return err
+}
+
+// Purge implements Store.
+func (ps *postgresStore) Purge(ctx context.Context, since, now time.Time) (int64, error) {
+	res, err := ps.db.ExecContext(ctx, purgeAttempts, since.UTC(), now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func scanRecord(row *sql.Row) (Record, error) {
+	var (
+		rec    Record
+		locked sql.NullTime
+	)
+	if err := row.Scan(&rec.Failures, &rec.LastFailure, &locked); err != nil {
+		return Record{}, err
+	}
+	rec.LockedUntil = locked.Time
+	return rec, nil
+}
+
+const (
+	getAttempt = `SELECT failures, last_failure, locked_until FROM login_attempts WHERE key = $1`
+
+	failAttempt = `INSERT INTO login_attempts (key, failures, last_failure) VALUES ($1, 1, $2)
+	ON CONFLICT (key) DO UPDATE SET
+		failures = CASE WHEN login_attempts.last_failure < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+		last_failure = EXCLUDED.last_failure
+	RETURNING failures, last_failure, locked_until`
+
+	forgiveAttempt = `UPDATE login_attempts SET failures = failures - 1 WHERE key = $1 AND failures > 0`
+
+	lockAttempt = `UPDATE login_attempts SET locked_until = $2 WHERE key = $1`
+
+	deleteAttempt = `DELETE FROM login_attempts WHERE key = $1`
+
+	purgeAttempts = `DELETE FROM login_attempts
+	WHERE last_failure < $1 AND (locked_until IS NULL OR locked_until < $2)`
+)
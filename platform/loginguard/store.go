@@ -0,0 +1,110 @@
+package loginguard
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Record is the failure history of one key.
+type Record struct {
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+// Store keeps records by key. Fail must be atomic, since concurrent guesses
+// for one key are exactly what the guard is counting.
+type Store interface {
+	// Get returns the zero Record for an unknown key.
+	Get(ctx context.Context, key string) (Record, error)
+	// Fail counts a failure at at, starting over from one when the previous
+	// failure happened before since, and returns the updated record.
+	Fail(ctx context.Context, key string, at, since time.Time) (Record, error)
+	// Forgive takes back one failure counted by Fail, never going below
+	// zero.
+	Forgive(ctx context.Context, key string) error
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+	// Purge deletes the records whose last failure happened before since and
+	// that are not locked at now.
+	Purge(ctx context.Context, since, now time.Time) (int64, error)
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore keeps records in this process. Each instance of a service
+// then counts on its own, which multiplies the attempts allowed by the
+// number of instances.
+func NewMemoryStore() Store {
+	return &memoryStore{records: make(map[string]Record)}
+}
+
+// Get implements Store.
+func (ms *memoryStore) Get(ctx context.Context, key string) (Record, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.records[key], nil
+}
+
+// Fail implements Store.
+func (ms *memoryStore) Fail(ctx context.Context, key string, at, since time.Time) (Record, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	rec := ms.records[key]
+	if rec.LastFailure.Before(since) {
+		rec.Failures = 0
+	}
+	rec.Failures++
+	rec.LastFailure = at
+	ms.records[key] = rec
+	return rec, nil
+}
+
+// Forgive implements Store.
+func (ms *memoryStore) Forgive(ctx context.Context, key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	rec, ok := ms.records[key]
+	if !ok || rec.Failures == 0 {
+		return nil
+	}
+	rec.Failures--
+	ms.records[key] = rec
+	return nil
+}
+
+// Lock implements Store.
+func (ms *memoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	rec := ms.records[key]
+	rec.LockedUntil = until
+	ms.records[key] = rec
+	return nil
+}
+
+// Reset implements Store.
+func (ms *memoryStore) Reset(ctx context.Context, key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.records, key)
+	return nil
+}
+
+// Purge implements Store.
+func (ms *memoryStore) Purge(ctx context.Context, since, now time.Time) (int64, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var n int64
+	for key, rec := range ms.records {
+		if rec.LastFailure.Before(since) && !rec.LockedUntil.After(now) {
+			delete(ms.records, key)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Allocations counts random numbers handed out per prefix, such as account
+// numbers per product, with the draws that collided with a number already
+// taken and the allocations given up. Collisions climbing for a prefix warn
+// that its number space is filling up before signups start failing.
+type Allocations struct {
+	allocated  *prometheus.CounterVec
+	collisions *prometheus.CounterVec
+	exhausted  *prometheus.CounterVec
+}
+
+// NewAllocations registers <namespace>_number_allocations_total,
+// <namespace>_number_collisions_total and
+// <namespace>_number_exhaustions_total, all labelled by prefix. Call it once
+// per namespace, typically from a package variable.
+func NewAllocations(namespace string) *Allocations {
+	counter := func(name, help string) *prometheus.CounterVec {
+		return promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      name,
+			Help:      help,
+		}, []string{"prefix"})
+	}
+	return &Allocations{
+		allocated:  counter("number_allocations_total", "Numbers allocated, by prefix."),
+		collisions: counter("number_collisions_total", "Numbers drawn again because the first draw was taken, by prefix."),
+		exhausted:  counter("number_exhaustions_total", "Allocations given up after too many collisions, by prefix."),
+	}
+}
+
+// Allocated counts a number handed out under prefix.
+func (a *Allocations) Allocated(prefix string) { a.allocated.WithLabelValues(prefix).Inc() }
+
+// Collided counts a draw under prefix that was already taken.
+func (a *Allocations) Collided(prefix string) { a.collisions.WithLabelValues(prefix).Inc() }
+
+// Exhausted counts an allocation under prefix given up.
+func (a *Allocations) Exhausted(prefix string) { a.exhausted.WithLabelValues(prefix).Inc() }
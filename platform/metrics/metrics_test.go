@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// scrape fetches the metrics page the way Prometheus does.
+func scrape(t *testing.T, url string) string {
+	t.Helper()
+	res, err := http.Get(url + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("scrape: status %d", res.StatusCode)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestScrapeServedRequests(t *testing.T) {
+	r := mux.NewRouter()
+	api := r.PathPrefix("/api").Subrouter()
+	api.Use(Route)
+	api.HandleFunc("/accounts/{account_number}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["account_number"] == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}).Methods("GET")
+	r.Handle("/metrics", Handler()).Methods("GET")
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	for _, path := range []string{"/api/accounts/0001", "/api/accounts/0002", "/api/accounts/missing"} {
+		res, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+
+	page := scrape(t, srv.URL)
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/api/accounts/{account_number}",status="200"} 2`,
+		`http_requests_total{method="GET",route="/api/accounts/{account_number}",status="404"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/api/accounts/{account_number}"} 3`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("scrape is missing %s", want)
+		}
+	}
+	if strings.Contains(page, "0001") {
+		t.Error("an account number became a label value")
+	}
+}
+
+func TestScrapeOperations(t *testing.T) {
+	errDeclined := errors.New("declined")
+	ops := NewOperations("test", func(err error) string { return "declined" })
+	ops.Record("deposit", "BRL", 10, nil)
+	ops.Record("deposit", "BRL", 0, errDeclined)
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	page := scrape(t, srv.URL)
+	for _, want := range []string{
+		`test_operations_total{error="",operation="deposit",outcome="success"} 1`,
+		`test_operations_total{error="declined",operation="deposit",outcome="failure"} 1`,
+		`test_operation_amount_count{currency="BRL",operation="deposit"} 1`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("scrape is missing %s", want)
+		}
+	}
+}
+
+func TestScrapeAllocations(t *testing.T) {
+	a := NewAllocations("test")
+	a.Allocated("123")
+	a.Collided("123")
+	a.Collided("123")
+	a.Exhausted("32")
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	page := scrape(t, srv.URL)
+	for _, want := range []string{
+		`test_number_allocations_total{prefix="123"} 1`,
+		`test_number_collisions_total{prefix="123"} 2`,
+		`test_number_exhaustions_total{prefix="32"} 1`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("scrape is missing %s", want)
+		}
+	}
+}
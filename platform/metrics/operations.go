@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Operations counts the business operations of a service, such as deposits
+// or transfers, by outcome and error type, and records how large the
+// successful ones were.
+type Operations struct {
+	total    *prometheus.CounterVec
+	amount   *prometheus.HistogramVec
+	classify func(error) string
+}
+
+// NewOperations registers <namespace>_operations_total and
+// <namespace>_operation_amount. classify names the type of a failure; it must
+// return one of a small, fixed set of names, since each becomes a series.
+// Call it once per namespace, typically from a package variable.
+func NewOperations(namespace string, classify func(error) string) *Operations {
+	return &Operations{
+		total: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operations_total",
+			Help:      "Business operations attempted, by operation, outcome and error type.",
+		}, []string{"operation", "outcome", "error"}),
+		amount: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "operation_amount",
+			Help:      "Amounts of successful business operations in major currency units, by operation and currency.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 11),
+		}, []string{"operation", "currency"}),
+		classify: classify,
+	}
+}
+
+// Record counts one attempt of operation that ended with err. The amount, in
+// major units of currency, is only observed when err is nil: a failed
+// request may carry any currency a client cares to send.
+func (o *Operations) Record(operation, currency string, amount float64, err error) {
+	if err != nil {
+		o.total.WithLabelValues(operation, OutcomeFailure, o.classify(err)).Inc()
+		return
+	}
+	o.total.WithLabelValues(operation, OutcomeSuccess, "").Inc()
+	o.amount.WithLabelValues(operation, currency).Observe(amount)
+}
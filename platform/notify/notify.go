@@ -0,0 +1,93 @@
+// Package notify delivers messages, such as password reset links, to
+// customers. Only local implementations exist; a mail or SMS provider plugs
+// in through Notifier.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// Message is one notification for a customer.
+type Message struct {
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// FromEnv returns the notifier named by NOTIFIER: "log" (the default) or
+// "file", which appends to NOTIFIER_FILE.
+func FromEnv() (Notifier, error) {
+	switch name := os.Getenv("NOTIFIER"); name {
+	case "", "log":
+		return NewLogNotifier(), nil
+	case "file":
+		path := os.Getenv("NOTIFIER_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("NOTIFIER=file needs NOTIFIER_FILE")
+		}
+		return NewFileNotifier(path), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", name)
+	}
+}
+
+type logNotifier struct {
+	logger *utils.Logger
+}
+
+// NewLogNotifier writes every message to the log. Messages may carry secrets
+// such as reset tokens, so use it for local development only.
+func NewLogNotifier() Notifier {
+	return &logNotifier{logger: utils.NewLogger("notify")}
+}
+
+// Notify implements Notifier.
+func (ln *logNotifier) Notify(ctx context.Context, msg Message) error {
+	ln.logger.WithContext(ctx).Infof("to=%s subject=%q body=%q", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+type fileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileNotifier appends every message to path as one JSON object per line.
+func NewFileNotifier(path string) Notifier {
+	return &fileNotifier{path: path}
+}
+
+// Notify implements Notifier.
+func (fn *fileNotifier) Notify(ctx context.Context, msg Message) error {
+	if msg.SentAt.IsZero() {
+		msg.SentAt = time.Now().UTC()
+	}
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	fn.mu.Lock()
+	defer fn.mu.Unlock()
+	f, err := os.OpenFile(fn.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
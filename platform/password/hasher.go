@@ -0,0 +1,206 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/adilsonmenechini/golabbank/platform/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUnknownHash     = errors.New("unrecognised password hash")
+	ErrPasswordTooLong = errors.New("password too long for bcrypt")
+	ErrUnknownScheme   = errors.New("unknown password hashing scheme")
+)
+
+// Scheme is one password hashing algorithm.
+type Scheme interface {
+	// Hash encodes password together with the scheme's parameters.
+	Hash(password string) (string, error)
+	// Recognises reports whether encoded was produced by this scheme.
+	Recognises(encoded string) bool
+	// Verify compares password against encoded in constant time.
+	Verify(password, encoded string) (bool, error)
+	// Outdated reports whether encoded used other parameters than the
+	// scheme's current ones.
+	Outdated(encoded string) bool
+}
+
+// Hasher hashes new passwords with its preferred scheme and verifies stored
+// hashes with whichever scheme produced them.
+type Hasher struct {
+	preferred Scheme
+	schemes   []Scheme
+	decoy     func() string
+}
+
+// NewHasher returns a Hasher that writes preferred hashes and still accepts
+// hashes from the legacy schemes.
+func NewHasher(preferred Scheme, legacy ...Scheme) *Hasher {
+	return &Hasher{
+		preferred: preferred,
+		schemes:   append([]Scheme{preferred}, legacy...),
+		decoy: sync.OnceValue(func() string {
+			encoded, _ := preferred.Hash("decoy password, never stored")
+			return encoded
+		}),
+	}
+}
+
+// HasherFromEnv prefers PASSWORD_HASHER ("argon2id", the default, or
+// "bcrypt") and accepts the other. Parameters come from BCRYPT_COST and
+// ARGON2_MEMORY (KiB), ARGON2_TIME and ARGON2_THREADS.
+func HasherFromEnv() (*Hasher, error) {
+	bc := Bcrypt{Cost: config.GetInt("BCRYPT_COST", 12)}
+	ar := Argon2id{
+		Memory:  uint32(config.GetInt("ARGON2_MEMORY", 19*1024)),
+		Time:    uint32(config.GetInt("ARGON2_TIME", 2)),
+		Threads: uint8(config.GetInt("ARGON2_THREADS", 1)),
+	}
+	switch name := os.Getenv("PASSWORD_HASHER"); name {
+	case "", "argon2id":
+		return NewHasher(ar, bc), nil
+	case "bcrypt":
+		return NewHasher(bc, ar), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScheme, name)
+	}
+}
+
+// Hash hashes password with the preferred scheme.
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+// Verify checks password against encoded. rehash is true when the password
+// matched but encoded should be replaced by Hash(password), because it was
+// written by another scheme or with other parameters.
+func (h *Hasher) Verify(password, encoded string) (ok, rehash bool, err error) {
+	for _, s := range h.schemes {
+		if !s.Recognises(encoded) {
+			continue
+		}
+		ok, err = s.Verify(password, encoded)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		return true, s != h.preferred || s.Outdated(encoded), nil
+	}
+	return false, false, ErrUnknownHash
+}
+
+// VerifyDecoy spends the time of a failed Verify without a stored hash, so
+// that a sign-in for an unknown account takes as long as a wrong password.
+func (h *Hasher) VerifyDecoy(password string) {
+	h.preferred.Verify(password, h.decoy())
+}
+
+// Bcrypt is the bcrypt scheme. Passwords longer than 72 bytes are refused.
+type Bcrypt struct {
+	Cost int
+}
+
+func (b Bcrypt) Hash(password string) (string, error) {
+	if len(password) > 72 {
+		return "", ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (b Bcrypt) Recognises(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (b Bcrypt) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (b Bcrypt) Outdated(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != b.Cost
+}
+
+// Argon2id is the Argon2id scheme, encoded in the PHC string format
+// $argon2id$v=19$m=<KiB>,t=<passes>,p=<threads>$<salt>$<key>.
+type Argon2id struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+const (
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+func (a Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.Time, a.Memory, a.Threads, argon2KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.Memory, a.Time, a.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (a Argon2id) Recognises(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (a Argon2id) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	got := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(got, key) == 1, nil
+}
+
+func (a Argon2id) Outdated(encoded string) bool {
+	params, _, key, err := decodeArgon2id(encoded)
+	return err != nil || params != a || len(key) != argon2KeyLen
+}
+
+func decodeArgon2id(encoded string) (Argon2id, []byte, []byte, error) {
+	var (
+		p       Argon2id
+		version int
+	)
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, ErrUnknownHash
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrUnknownHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, ErrUnknownHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrUnknownHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, ErrUnknownHash
+	}
+	return p, salt, key, nil
+}
@@ -0,0 +1,112 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	testArgon2id = Argon2id{Memory: 64, Time: 1, Threads: 1}
+	testBcrypt   = Bcrypt{Cost: bcrypt.MinCost}
+)
+
+func TestArgon2idRoundTrip(t *testing.T) {
+	h := NewHasher(testArgon2id)
+	encoded, err := h.Hash("Correct-horse-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("encoded = %q, want the PHC format", encoded)
+	}
+	if again, _ := h.Hash("Correct-horse-7"); again == encoded {
+		t.Fatal("two hashes of one password are equal; the salt is not random")
+	}
+
+	tests := []struct {
+		name       string
+		password   string
+		ok, rehash bool
+	}{
+		{"right password", "Correct-horse-7", true, false},
+		{"wrong password", "Correct-horse-8", false, false},
+		{"empty password", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash, err := h.Verify(tt.password, encoded)
+			if err != nil || ok != tt.ok || rehash != tt.rehash {
+				t.Fatalf("Verify = %v, %v, %v; want %v, %v, nil", ok, rehash, err, tt.ok, tt.rehash)
+			}
+		})
+	}
+}
+
+// TestLegacyHashesAreUpgraded verifies hashes written by another scheme or
+// with other parameters and flags them for rehashing with the preferred one.
+func TestLegacyHashesAreUpgraded(t *testing.T) {
+	h := NewHasher(testArgon2id, testBcrypt)
+	bcryptHash, _ := testBcrypt.Hash("Correct-horse-7")
+	weakArgon, _ := Argon2id{Memory: 32, Time: 1, Threads: 1}.Hash("Correct-horse-7")
+
+	tests := []struct {
+		name       string
+		password   string
+		encoded    string
+		ok, rehash bool
+	}{
+		{"bcrypt", "Correct-horse-7", bcryptHash, true, true},
+		{"bcrypt, wrong password", "Correct-horse-8", bcryptHash, false, false},
+		{"older argon2id parameters", "Correct-horse-7", weakArgon, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash, err := h.Verify(tt.password, tt.encoded)
+			if err != nil || ok != tt.ok || rehash != tt.rehash {
+				t.Fatalf("Verify = %v, %v, %v; want %v, %v, nil", ok, rehash, err, tt.ok, tt.rehash)
+			}
+		})
+	}
+
+	upgraded, err := h.Hash("Correct-horse-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !testArgon2id.Recognises(upgraded) {
+		t.Fatalf("rehash produced %q, want an argon2id hash", upgraded)
+	}
+	if ok, rehash, _ := h.Verify("Correct-horse-7", upgraded); !ok || rehash {
+		t.Fatal("the upgraded hash does not verify cleanly")
+	}
+}
+
+func TestVerifyRejectsUnknownHashes(t *testing.T) {
+	bcryptHash, _ := testBcrypt.Hash("Correct-horse-7")
+
+	tests := []struct {
+		name    string
+		hasher  *Hasher
+		encoded string
+	}{
+		{"plain text", NewHasher(testArgon2id, testBcrypt), "Correct-horse-7"},
+		{"malformed argon2id", NewHasher(testArgon2id, testBcrypt), "$argon2id$v=19$m=64$salt$key"},
+		{"scheme not accepted", NewHasher(testArgon2id), bcryptHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, _, err := tt.hasher.Verify("Correct-horse-7", tt.encoded)
+			if ok || !errors.Is(err, ErrUnknownHash) {
+				t.Fatalf("Verify = %v, %v; want false, ErrUnknownHash", ok, err)
+			}
+		})
+	}
+}
+
+func TestBcryptRefusesLongPasswords(t *testing.T) {
+	if _, err := testBcrypt.Hash(strings.Repeat("x", 73)); !errors.Is(err, ErrPasswordTooLong) {
+		t.Fatalf("err = %v, want ErrPasswordTooLong", err)
+	}
+}
@@ -0,0 +1,183 @@
+// Package password checks new passwords against the bank's policy and hashes
+// them with a pluggable scheme.
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/adilsonmenechini/golabbank/platform/config"
+)
+
+var ErrPolicy = errors.New("password does not meet the password policy")
+
+//go:embed common-passwords.txt
+var commonPasswords string
+
+// Violation is one policy rule a password broke.
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PolicyError lists every rule a password broke. It matches ErrPolicy.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return ErrPolicy.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrPolicy
+}
+
+// Policy is the set of rules a new password must satisfy.
+type Policy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	denylist      map[string]struct{}
+}
+
+// NewPolicy returns a policy that also refuses every password in denylist,
+// compared case-insensitively.
+func NewPolicy(p Policy, denylist []string) *Policy {
+	p.denylist = make(map[string]struct{}, len(denylist))
+	for _, pw := range denylist {
+		p.denylist[strings.ToLower(pw)] = struct{}{}
+	}
+	return &p
+}
+
+// PolicyFromEnv builds the policy from PASSWORD_MIN_LENGTH (default 12),
+// PASSWORD_MAX_LENGTH (default 128), PASSWORD_REQUIRE_UPPER, _LOWER and
+// _DIGIT (default true), PASSWORD_REQUIRE_SYMBOL (default false) and
+// PASSWORD_DENYLIST_FILE, one password per line. Without a denylist file the
+// built-in list of common passwords is used.
+func PolicyFromEnv() (*Policy, error) {
+	var (
+		denylist []string
+		err      error
+	)
+	if path := os.Getenv("PASSWORD_DENYLIST_FILE"); path != "" {
+		denylist, err = readDenylistFile(path)
+	} else {
+		denylist, err = ReadDenylist(strings.NewReader(commonPasswords))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading password denylist: %w", err)
+	}
+	return NewPolicy(Policy{
+		MinLength:     config.GetInt("PASSWORD_MIN_LENGTH", 12),
+		MaxLength:     config.GetInt("PASSWORD_MAX_LENGTH", 128),
+		RequireUpper:  config.GetBool("PASSWORD_REQUIRE_UPPER", true),
+		RequireLower:  config.GetBool("PASSWORD_REQUIRE_LOWER", true),
+		RequireDigit:  config.GetBool("PASSWORD_REQUIRE_DIGIT", true),
+		RequireSymbol: config.GetBool("PASSWORD_REQUIRE_SYMBOL", false),
+	}, denylist), nil
+}
+
+// ReadDenylist reads one password per line, skipping blank lines and lines
+// starting with #.
+func ReadDenylist(r io.Reader) ([]string, error) {
+	var out []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out, sc.Err()
+}
+
+func readDenylistFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadDenylist(f)
+}
+
+// Check returns a *PolicyError naming field when password breaks any rule.
+// personal holds values such as the customer's email and name, which the
+// password must not contain.
+func (p *Policy) Check(field, password string, personal ...string) error {
+	var vs []Violation
+	add := func(rule, msg string) {
+		vs = append(vs, Violation{Field: field, Rule: rule, Message: msg})
+	}
+
+	n := utf8.RuneCountInString(password)
+	if n < p.MinLength {
+		add("min_length", fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		add("max_length", fmt.Sprintf("must be at most %d characters", p.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		add("uppercase", "must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		add("lowercase", "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		add("digit", "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		add("symbol", "must contain a symbol")
+	}
+
+	lowered := strings.ToLower(password)
+	if _, ok := p.denylist[lowered]; ok {
+		add("common", "is too common")
+	}
+	for _, s := range personal {
+		s = strings.ToLower(s)
+		if local, _, ok := strings.Cut(s, "@"); ok {
+			s = local
+		}
+		if len(s) >= 4 && strings.Contains(lowered, s) {
+			add("personal", "must not contain your name or email")
+			break
+		}
+	}
+
+	if len(vs) > 0 {
+		return &PolicyError{Violations: vs}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package password
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	strict := NewPolicy(Policy{
+		MinLength:     12,
+		MaxLength:     20,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}, []string{"Password123!"})
+
+	tests := []struct {
+		name     string
+		password string
+		personal []string
+		want     []string
+	}{
+		{"valid", "Correct-horse-7", nil, nil},
+		{"too short", "Sh0rt!", nil, []string{"min_length"}},
+		{"too long", "Far-too-long-passw0rd", nil, []string{"max_length"}},
+		{"length counts runes", "Ação-segura-1", nil, nil},
+		{"no uppercase", "correct-horse-7", nil, []string{"uppercase"}},
+		{"no lowercase", "CORRECT-HORSE-7", nil, []string{"lowercase"}},
+		{"no digit", "Correct-horse-x", nil, []string{"digit"}},
+		{"no symbol", "CorrectHorse77", nil, []string{"symbol"}},
+		{"space counts as symbol", "Correct horse 7", nil, nil},
+		{"denylisted", "Password123!", nil, []string{"common"}},
+		{"denylist ignores case", "PASSWORD123!x", nil, nil},
+		{"denylisted in other case", "pASSWORD123!", nil, []string{"common"}},
+		{"contains the email", "Xalice-2024!", []string{"alice@example.com"}, []string{"personal"}},
+		{"contains the name", "Silva-Rules-1", []string{"a@b.c", "Silva"}, []string{"personal"}},
+		{"short personal values ignored", "Correct-bob-7", []string{"Bob"}, nil},
+		{"every violation reported", "abc", nil, []string{"min_length", "uppercase", "digit", "symbol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := strict.Check("password", tt.password, tt.personal...)
+			var got []string
+			var pe *PolicyError
+			if errors.As(err, &pe) {
+				for _, v := range pe.Violations {
+					if v.Field != "password" {
+						t.Errorf("violation %s names field %q", v.Rule, v.Field)
+					}
+					got = append(got, v.Rule)
+				}
+			} else if err != nil {
+				t.Fatalf("err = %v, want a *PolicyError", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("violations = %v, want %v", got, tt.want)
+			}
+			if tt.want != nil && !errors.Is(err, ErrPolicy) {
+				t.Fatalf("err = %v does not match ErrPolicy", err)
+			}
+		})
+	}
+}
+
+func TestPolicyWithoutMaxLength(t *testing.T) {
+	p := NewPolicy(Policy{MinLength: 1}, nil)
+	if err := p.Check("password", strings.Repeat("x", 1000)); err != nil {
+		t.Fatalf("no maximum configured: %v", err)
+	}
+}
+
+func TestReadDenylist(t *testing.T) {
+	got, err := ReadDenylist(strings.NewReader("# comment\n\n  secret  \nletmein\n#another\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"secret", "letmein"}; !slices.Equal(got, want) {
+		t.Fatalf("denylist = %q, want %q", got, want)
+	}
+
+	builtin, err := ReadDenylist(strings.NewReader(commonPasswords))
+	if err != nil || len(builtin) == 0 {
+		t.Fatalf("built-in denylist: %d entries, err = %v", len(builtin), err)
+	}
+}
@@ -0,0 +1,61 @@
+// Package rbac restricts routes to staff roles. It reads the claims left in
+// the request context by a service's token middleware, so its handlers must
+// be wrapped by that middleware.
+package rbac
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/adilsonmenechini/golabbank/platform/audit"
+	"github.com/adilsonmenechini/golabbank/platform/identity"
+	"github.com/adilsonmenechini/golabbank/platform/response"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+	"github.com/gorilla/mux"
+)
+
+type Enforcer struct {
+	audit audit.Auditor
+	rs    *response.Presenter
+}
+
+// NewEnforcer writes refused requests to auditor.
+func NewEnforcer(auditor audit.Auditor) *Enforcer {
+	return &Enforcer{
+		audit: auditor,
+		rs:    response.NewPresenter(),
+	}
+}
+
+// Require declares the roles allowed on a route; tokens without a role claim
+// are customers. Refusals are written to the audit log.
+func (e *Enforcer) Require(roles ...identity.Role) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tk, ok := utils.ClaimsFromContext(r.Context())
+			if !ok {
+				e.rs.ResponseError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+			role, err := identity.ParseRole(tk.Role)
+			if err == nil && slices.Contains(roles, role) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			e.audit.Record(r.Context(), audit.Event{
+				Action:     "route.access",
+				CustomerID: tk.ID,
+				Role:       tk.Role,
+				Resource:   r.Method + " " + r.URL.Path,
+				Outcome:    audit.OutcomeDenied,
+				Detail:     "role not allowed",
+			})
+			e.rs.ResponseError(w, http.StatusForbidden, "Forbidden")
+		})
+	}
+}
+
+// Allow wraps a single handler with Require.
+func (e *Enforcer) Allow(h http.HandlerFunc, roles ...identity.Role) http.Handler {
+	return e.Require(roles...)(h)
+}
@@ -0,0 +1,69 @@
+// Package response writes the JSON envelope every service answers with.
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Presenter writes the JSON envelope of every response. Responses are
+// logged once by utils.RequestID, with their status and request ID.
+type Presenter struct{}
+
+func NewPresenter() *Presenter {
+	return &Presenter{}
+}
+
+func (pa *Presenter) ResponseSuccess(w http.ResponseWriter, statusCode int, res string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     "success",
+		"statusCode": statusCode,
+		"message":    res,
+	})
+}
+
+func (pa *Presenter) ResponseError(w http.ResponseWriter, statusCode int, res string) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     "error",
+		"statusCode": statusCode,
+		"message":    res,
+	})
+}
+
+func (pa *Presenter) ResponseErrorToken(w http.ResponseWriter, statusCode int, res string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     "error",
+		"statusCode": statusCode,
+		"message":    res,
+	})
+}
+
+func (pa *Presenter) ResponseData(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     "success",
+		"statusCode": statusCode,
+		"data":       data,
+	})
+}
+
+// ResponseViolations is ResponseError with the list of field-level problems
+// that caused it.
+func (pa *Presenter) ResponseViolations(w http.ResponseWriter, statusCode int, res string, violations interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     "error",
+		"statusCode": statusCode,
+		"message":    res,
+		"violations": violations,
+	})
+}
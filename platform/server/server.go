@@ -0,0 +1,166 @@
+// Package server runs a service's HTTP handler until the process is told to
+// stop, then drains in-flight requests before releasing what the service
+// holds, such as its database pool.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/adilsonmenechini/golabbank/platform/config"
+	"github.com/adilsonmenechini/golabbank/platform/utils"
+)
+
+// DefaultAddr listens on every interface, so the service can be reached from
+// outside its container.
+const DefaultAddr = ":8000"
+
+var ErrTLSConfig = errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+
+// Config tunes the HTTP server. Zero durations fall back to the defaults
+// noted on each field.
+type Config struct {
+	Addr string
+	// ReadTimeout bounds reading a whole request (default 15s).
+	ReadTimeout time.Duration
+	// WriteTimeout bounds writing a response (default 15s). Handlers that
+	// stream a response for longer, such as statement exports, push their
+	// own deadline forward with http.ResponseController.SetWriteDeadline.
+	WriteTimeout time.Duration
+	// IdleTimeout closes keep-alive connections left unused (default 60s).
+	IdleTimeout time.Duration
+	// ShutdownTimeout is how long in-flight requests get to finish once the
+	// server is stopping (default 20s); those still running are cut off.
+	ShutdownTimeout time.Duration
+	// CertFile and KeyFile, both PEM, switch the server to HTTPS.
+	CertFile string
+	KeyFile  string
+}
+
+// ConfigFromEnv reads HTTP_ADDR, HTTP_READ_TIMEOUT, HTTP_WRITE_TIMEOUT,
+// HTTP_IDLE_TIMEOUT, HTTP_SHUTDOWN_TIMEOUT, TLS_CERT_FILE and TLS_KEY_FILE.
+func ConfigFromEnv() Config {
+	return Config{
+		Addr:            os.Getenv("HTTP_ADDR"),
+		ReadTimeout:     config.GetDuration("HTTP_READ_TIMEOUT", 0),
+		WriteTimeout:    config.GetDuration("HTTP_WRITE_TIMEOUT", 0),
+		IdleTimeout:     config.GetDuration("HTTP_IDLE_TIMEOUT", 0),
+		ShutdownTimeout: config.GetDuration("HTTP_SHUTDOWN_TIMEOUT", 0),
+		CertFile:        os.Getenv("TLS_CERT_FILE"),
+		KeyFile:         os.Getenv("TLS_KEY_FILE"),
+	}
+}
+
+type Server struct {
+	logger  *utils.Logger
+	cfg     Config
+	handler http.Handler
+	closers []func(context.Context) error
+}
+
+func New(cfg Config, handler http.Handler) *Server {
+	if cfg.Addr == "" {
+		cfg.Addr = DefaultAddr
+	}
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = 15 * time.Second
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = 15 * time.Second
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = 60 * time.Second
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = 20 * time.Second
+	}
+	return &Server{
+		logger:  utils.NewLogger("server"),
+		cfg:     cfg,
+		handler: handler,
+	}
+}
+
+// OnShutdown registers fn to run once the server has stopped serving,
+// whether it drained cleanly or failed. Functions run in the reverse order of
+// registration, so register the database before what uses it. Their context
+// allows them ShutdownTimeout together, counted from when draining ended.
+func (s *Server) OnShutdown(fn func(context.Context) error) {
+	s.closers = append(s.closers, fn)
+}
+
+// Run listens on the configured address and serves until ctx is done or the
+// process receives SIGINT or SIGTERM.
+func (s *Server) Run(ctx context.Context) error {
+	if (s.cfg.CertFile == "") != (s.cfg.KeyFile == "") {
+		return errors.Join(ErrTLSConfig, s.close())
+	}
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return errors.Join(err, s.close())
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve is Run on a listener of the caller's, such as one on a random port
+// in tests. It returns nil after a clean shutdown.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Handler:      s.handler,
+		ReadTimeout:  s.cfg.ReadTimeout,
+		WriteTimeout: s.cfg.WriteTimeout,
+		IdleTimeout:  s.cfg.IdleTimeout,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		if s.cfg.CertFile != "" {
+			s.logger.Infof("listening on https://%s", ln.Addr())
+			errc <- srv.ServeTLS(ln, s.cfg.CertFile, s.cfg.KeyFile)
+		} else {
+			s.logger.Infof("listening on http://%s", ln.Addr())
+			errc <- srv.Serve(ln)
+		}
+	}()
+
+	select {
+	case err := <-errc:
+		return errors.Join(fmt.Errorf("serving http: %w", err), s.close())
+	case <-ctx.Done():
+	}
+	stop()
+
+	s.logger.Infof("shutting down, draining connections for up to %s", s.cfg.ShutdownTimeout)
+	sctx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	err := srv.Shutdown(sctx)
+	if err != nil {
+		err = fmt.Errorf("draining connections: %w", err)
+		srv.Close()
+	}
+	return errors.Join(err, s.close())
+}
+
+// close runs the closers with a deadline of their own: the one used to drain
+// connections may already have expired.
+func (s *Server) close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		if err := s.closers[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
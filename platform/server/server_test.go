@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestClosersOutliveAnExpiredDrain keeps a request running past
+// ShutdownTimeout; the closers must still get a live context.
+func TestClosersOutliveAnExpiredDrain(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	srv := New(Config{ShutdownTimeout: 50 * time.Millisecond}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	var closerErr error
+	closed := false
+	srv.OnShutdown(func(ctx context.Context) error {
+		closed = true
+		closerErr = ctx.Err()
+		return nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, ln) }()
+
+	go http.Get("http://" + ln.Addr().String())
+	<-started
+	cancel()
+
+	if err := <-done; err == nil {
+		t.Fatal("Serve returned nil although draining timed out")
+	}
+	if !closed {
+		t.Fatal("closer did not run")
+	}
+	if closerErr != nil {
+		t.Fatalf("closer got a done context: %v", closerErr)
+	}
+}
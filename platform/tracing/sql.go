@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Connector wraps c so that every statement run on its connections, inside a
+// transaction or not, is traced as a client span of the calling context. The
+// span carries the statement text, which holds placeholders and never the
+// argument values. Statements prepared explicitly are not traced.
+func Connector(c driver.Connector, system, dbName string) driver.Connector {
+	return &connector{Connector: c, system: system, dbName: dbName}
+}
+
+type connector struct {
+	driver.Connector
+	system string
+	dbName string
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: cn, c: c}, nil
+}
+
+// conn passes everything through to the driver's connection, opening a span
+// around each statement.
+type conn struct {
+	driver.Conn
+	c *connector
+}
+
+func (cn *conn) startStatement(ctx context.Context, query string) (context.Context, Span) {
+	op := strings.ToUpper(strings.SplitN(strings.TrimSpace(query), " ", 2)[0])
+	return otel.Tracer(instrumentation).Start(ctx, op+" "+cn.c.dbName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", cn.c.system),
+			attribute.String("db.name", cn.c.dbName),
+			attribute.String("db.operation", op),
+			attribute.String("db.statement", query),
+		),
+	)
+}
+
+func endStatement(span Span, err error) {
+	if !errors.Is(err, driver.ErrSkip) {
+		Fail(span, err)
+	}
+	span.End()
+}
+
+func (cn *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ex, ok := cn.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := cn.startStatement(ctx, query)
+	res, err := ex.ExecContext(ctx, query, args)
+	endStatement(span, err)
+	return res, err
+}
+
+func (cn *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := cn.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := cn.startStatement(ctx, query)
+	rows, err := q.QueryContext(ctx, query, args)
+	endStatement(span, err)
+	return rows, err
+}
+
+func (cn *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := cn.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return cn.Conn.Prepare(query)
+}
+
+func (cn *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := cn.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return cn.Conn.Begin()
+}
+
+func (cn *conn) Ping(ctx context.Context) error {
+	if p, ok := cn.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (cn *conn) ResetSession(ctx context.Context) error {
+	if r, ok := cn.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (cn *conn) IsValid() bool {
+	if v, ok := cn.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
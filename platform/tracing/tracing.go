@@ -0,0 +1,108 @@
+// Package tracing records what a request did across the handler, usecase and
+// repository layers as OpenTelemetry spans. Trace context travels between
+// services in W3C traceparent headers.
+//
+// Spans go nowhere until Setup installs an exporter; the no-op tracer that is
+// in place before then costs next to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentation = "github.com/adilsonmenechini/golabbank/platform/tracing"
+
+// Span is the part of an OpenTelemetry span the layers use.
+type Span = trace.Span
+
+func init() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+// Setup installs the exporter named by OTEL_TRACES_EXPORTER:
+//   - "otlp" sends spans over OTLP/HTTP to OTEL_EXPORTER_OTLP_ENDPOINT
+//     (default localhost:4318), configured by the standard OTEL_EXPORTER_OTLP_*
+//     variables;
+//   - "stdout" prints them, one JSON document per span, for local use;
+//   - "none" (the default) drops them.
+//
+// service names the process unless OTEL_SERVICE_NAME says otherwise. The
+// returned function flushes pending spans and must be called on shutdown.
+func Setup(ctx context.Context, service string) (func(context.Context) error, error) {
+	var (
+		exp sdktrace.SpanExporter
+		err error
+	)
+	switch name := os.Getenv("OTEL_TRACES_EXPORTER"); name {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		exp, err = otlptracehttp.New(ctx)
+	case "stdout":
+		exp, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Install(ctx, exp, service)
+}
+
+// Install makes a provider sending every span to exp the global one. Spans
+// are exported in batches, except for the in-memory exporter: tests read its
+// spans back as soon as the call under test returns.
+func Install(ctx context.Context, exp sdktrace.SpanExporter, service string) (func(context.Context) error, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", service)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	export := sdktrace.WithBatcher(exp)
+	if _, ok := exp.(*tracetest.InMemoryExporter); ok {
+		export = sdktrace.WithSyncer(exp)
+	}
+	tp := sdktrace.NewTracerProvider(export, sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// NewMemoryExporter keeps finished spans in memory, for tests that assert on
+// the spans a call produced. Pass it to Install.
+func NewMemoryExporter() *tracetest.InMemoryExporter {
+	return tracetest.NewInMemoryExporter()
+}
+
+// Start opens a span named name as a child of the span in ctx, if any. The
+// caller must End it.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Fail marks span as failed with err. A nil err leaves the span untouched.
+func Fail(span Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
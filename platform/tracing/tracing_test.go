@@ -0,0 +1,151 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// install sends spans to a fresh in-memory exporter for the rest of the test.
+func install(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exp := NewMemoryExporter()
+	shutdown, err := Install(context.Background(), exp, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { shutdown(context.Background()) })
+	return exp
+}
+
+// spanNamed returns the only finished span called name.
+func spanNamed(t *testing.T, exp *tracetest.InMemoryExporter, name string) tracetest.SpanStub {
+	t.Helper()
+	var found []tracetest.SpanStub
+	for _, s := range exp.GetSpans() {
+		if s.Name == name {
+			found = append(found, s)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("%d spans named %q among %d, want 1", len(found), name, len(exp.GetSpans()))
+	}
+	return found[0]
+}
+
+func attr(s tracetest.SpanStub, key string) attribute.Value {
+	for _, kv := range s.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestRouteContinuesTheCallersTrace(t *testing.T) {
+	exp := install(t)
+	r := mux.NewRouter()
+	r.Use(Route)
+	r.HandleFunc("/accounts/{account_number}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := Start(r.Context(), "handler")
+		span.End()
+		if mux.Vars(r)["account_number"] == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	ctx, caller := Start(context.Background(), "caller")
+	req := httptest.NewRequest(http.MethodGet, "/accounts/0001", nil)
+	Inject(ctx, req.Header)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	caller.End()
+
+	server := spanNamed(t, exp, "GET /accounts/{account_number}")
+	if server.SpanKind != trace.SpanKindServer {
+		t.Errorf("kind = %s, want server", server.SpanKind)
+	}
+	if server.Parent.SpanID() != caller.SpanContext().SpanID() || server.SpanContext.TraceID() != caller.SpanContext().TraceID() {
+		t.Error("the server span does not continue the caller's trace")
+	}
+	if got := attr(server, "http.status_code").AsInt64(); got != http.StatusOK {
+		t.Errorf("http.status_code = %d, want 200", got)
+	}
+	if handler := spanNamed(t, exp, "handler"); handler.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Error("spans opened by the handler are not children of the server span")
+	}
+
+	exp.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/accounts/broken", nil))
+	server = spanNamed(t, exp, "GET /accounts/{account_number}")
+	if server.Parent.IsValid() {
+		t.Error("a request without traceparent got a parent span")
+	}
+	if server.Status.Code != codes.Error {
+		t.Errorf("status = %v for a 500, want an error", server.Status.Code)
+	}
+}
+
+func TestConnectorTracesStatements(t *testing.T) {
+	exp := install(t)
+	db := sql.OpenDB(Connector(fakeConnector{}, "postgresql", "bank"))
+	defer db.Close()
+
+	ctx, parent := Start(context.Background(), "repository")
+	if _, err := db.ExecContext(ctx, "INSERT INTO accounts (id) VALUES ($1)", "secret-id"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.QueryContext(ctx, "SELECT id FROM accounts"); !errors.Is(err, errQuery) {
+		t.Fatalf("err = %v, want the driver's", err)
+	}
+	parent.End()
+
+	insert := spanNamed(t, exp, "INSERT bank")
+	if insert.SpanKind != trace.SpanKindClient || insert.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("the statement span is not a client child of the calling span")
+	}
+	if got := attr(insert, "db.statement").AsString(); got != "INSERT INTO accounts (id) VALUES ($1)" {
+		t.Errorf("db.statement = %q", got)
+	}
+	for _, kv := range insert.Attributes {
+		if kv.Value.Emit() == "secret-id" {
+			t.Errorf("argument value recorded as %s", kv.Key)
+		}
+	}
+
+	if sel := spanNamed(t, exp, "SELECT bank"); sel.Status.Code != codes.Error {
+		t.Errorf("status = %v for a failed query, want an error", sel.Status.Code)
+	}
+}
+
+var errQuery = errors.New("query failed")
+
+// fakeConnector hands out connections that accept any statement and fail
+// every query.
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return nil, errQuery
+}
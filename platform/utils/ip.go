@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/adilsonmenechini/golabbank/platform/config"
+)
+
+// ClientIP returns the address the request came from. X-Forwarded-For is
+// only believed when TRUST_PROXY_HEADERS is set, since clients can send it
+// themselves. Every proxy appends the address it received the request from,
+// so only entries on the right were written by our own proxies: ClientIP
+// walks the header from the right, skipping the addresses listed in
+// TRUSTED_PROXIES, and returns the first one that is not ours. Anything left
+// of it may have been made up by the client.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !config.GetBool("TRUST_PROXY_HEADERS", false) {
+		return host
+	}
+	hops := forwardedFor(r)
+	if len(hops) == 0 {
+		return host
+	}
+	trusted := trustedProxies()
+	for i := len(hops) - 1; i > 0; i-- {
+		addr, err := netip.ParseAddr(hops[i])
+		if err != nil || !isTrusted(trusted, addr) {
+			return hops[i]
+		}
+	}
+	return hops[0]
+}
+
+// forwardedFor lists the X-Forwarded-For entries of every such header, left
+// to right.
+func forwardedFor(r *http.Request) []string {
+	var hops []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(v, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// trustedProxies parses TRUSTED_PROXIES, a comma-separated list of addresses
+// or CIDR ranges. Unset, no forwarded entry is skipped: the rightmost one,
+// written by the proxy in front of us, is the client.
+func trustedProxies() []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, v := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		if !strings.Contains(v, "/") {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				log.Printf("invalid address %q in TRUSTED_PROXIES, ignoring it", v)
+				continue
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(v)
+		if err != nil {
+			log.Printf("invalid range %q in TRUSTED_PROXIES, ignoring it", v)
+			continue
+		}
+		prefixes = append(prefixes, p)
+	}
+	return prefixes
+}
+
+func isTrusted(trusted []netip.Prefix, addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
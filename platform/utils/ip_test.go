@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		name    string
+		trust   string
+		proxies string
+		xff     []string
+		want    string
+	}{
+		{name: "headers not trusted", trust: "false", xff: []string{"1.1.1.1"}, want: "10.0.0.1"},
+		{name: "no header", trust: "true", want: "10.0.0.1"},
+		{name: "single proxy", trust: "true", xff: []string{"1.1.1.1"}, want: "1.1.1.1"},
+		{name: "spoofed entries ignored", trust: "true", xff: []string{"6.6.6.6, 1.1.1.1"}, want: "1.1.1.1"},
+		{name: "trusted hops skipped", trust: "true", proxies: "10.1.0.0/16, 10.2.0.5", xff: []string{"6.6.6.6, 1.1.1.1, 10.1.2.3", "10.2.0.5"}, want: "1.1.1.1"},
+		{name: "all hops trusted", trust: "true", proxies: "10.0.0.0/8", xff: []string{"10.1.1.1, 10.2.2.2"}, want: "10.1.1.1"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("TRUST_PROXY_HEADERS", c.trust)
+			t.Setenv("TRUSTED_PROXIES", c.proxies)
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = "10.0.0.1:4321"
+			for _, v := range c.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := ClientIP(r); got != c.want {
+				t.Fatalf("ClientIP = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
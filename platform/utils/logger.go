@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Logger writes leveled, structured records through log/slog. The component
+// given to NewLogger becomes a field of every record, and WithContext adds
+// the request ID and customer of the request being served.
+type Logger struct {
+	component string
+	once      sync.Once
+	sl        *slog.Logger
+}
+
+type Loggers interface {
+	Debug(v ...interface{})
+	Debugf(format string, v ...interface{})
+	Info(v ...interface{})
+	Infof(format string, v ...interface{})
+	Warn(v ...interface{})
+	Warnf(format string, v ...interface{})
+	Error(v ...interface{})
+	Errorf(format string, v ...interface{})
+}
+
+// baseLogger is built on first use rather than at start-up, since loggers are
+// created in package init functions before the .env file has been loaded.
+// LOG_FORMAT is "json" (the default) or "text"; LOG_LEVEL is "debug",
+// "info" (the default), "warn" or "error". It also becomes the destination
+// of the standard log package.
+var baseLogger = sync.OnceValue(func() *slog.Logger {
+	opts := &slog.HandlerOptions{Level: logLevel(os.Getenv("LOG_LEVEL"))}
+	var h slog.Handler
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "text") {
+		h = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		h = slog.NewJSONHandler(os.Stdout, opts)
+	}
+	l := slog.New(h)
+	slog.SetDefault(l)
+	return l
+})
+
+func logLevel(s string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+func NewLogger(component string) *Logger {
+	return &Logger{component: component}
+}
+
+func (l *Logger) slog() *slog.Logger {
+	l.once.Do(func() {
+		if l.sl == nil {
+			l.sl = baseLogger().With("component", l.component)
+		}
+	})
+	return l.sl
+}
+
+// WithContext returns a logger whose records also carry the request ID, the
+// trace and the authenticated customer found in ctx, if any.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	sl := l.slog()
+	if id, ok := RequestIDFromContext(ctx); ok {
+		sl = sl.With("request_id", id)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		sl = sl.With("trace_id", sc.TraceID().String())
+	}
+	if tk, ok := ClaimsFromContext(ctx); ok {
+		sl = sl.With("customer_id", tk.ID)
+	}
+	return &Logger{component: l.component, sl: sl}
+}
+
+// print and printf only format the message when the level is enabled.
+func (l *Logger) print(level slog.Level, v []interface{}) {
+	if sl := l.slog(); sl.Enabled(context.Background(), level) {
+		sl.Log(context.Background(), level, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+	}
+}
+
+func (l *Logger) printf(level slog.Level, format string, v []interface{}) {
+	if sl := l.slog(); sl.Enabled(context.Background(), level) {
+		sl.Log(context.Background(), level, fmt.Sprintf(format, v...))
+	}
+}
+
+// Create Non-Formatted Logs
+func (l *Logger) Debug(v ...interface{}) {
+	l.print(slog.LevelDebug, v)
+}
+func (l *Logger) Info(v ...interface{}) {
+	l.print(slog.LevelInfo, v)
+}
+func (l *Logger) Warn(v ...interface{}) {
+	l.print(slog.LevelWarn, v)
+}
+func (l *Logger) Error(v ...interface{}) {
+	l.print(slog.LevelError, v)
+}
+
+// Create Format Enabled Logs
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	l.printf(slog.LevelDebug, format, v)
+}
+func (l *Logger) Infof(format string, v ...interface{}) {
+	l.printf(slog.LevelInfo, format, v)
+}
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	l.printf(slog.LevelWarn, format, v)
+}
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	l.printf(slog.LevelError, format, v)
+}
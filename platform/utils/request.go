@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader carries the correlation ID of a request, both from clients
+// and proxies that already assigned one and back to the caller.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// validRequestID accepts IDs of printable ASCII only, so a client cannot
+// forge extra fields or lines in the logs through the header.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestID is the outermost middleware of every service. It keeps the
+// X-Request-ID sent by the caller or assigns a new one, echoes it in the
+// response, puts it in the request context for Logger.WithContext and logs
+// one line per request once it is done.
+func RequestID(next http.Handler) http.Handler {
+	logger := NewLogger("http")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = GenerateUUID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		ctx := ContextWithRequestID(r.Context(), id)
+
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r.WithContext(ctx))
+
+		logger.WithContext(ctx).slog().Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", sw.status,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+		)
+	})
+}
+
+// statusWriter remembers the status code written through it.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}